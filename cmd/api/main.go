@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Napat/mcpserver-demo/internal/router"
 	"github.com/Napat/mcpserver-demo/pkg/database"
@@ -52,7 +57,8 @@ func main() {
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     allowedOrigins,
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "Mcp-Session-Id", "Mcp-Protocol-Version"},
+		ExposeHeaders:    []string{"Mcp-Session-Id"},
 		AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete, http.MethodOptions},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours
@@ -84,7 +90,12 @@ func main() {
 	}
 	logger.Info("Connected to database successfully")
 
-	router.SetupRoutes(e, db, logger)
+	// หยุดการทำงานอย่างนุ่มนวลเมื่อได้รับ SIGINT หรือ SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	services := router.NewServices(db, logger)
+	router.SetupRoutes(e, services, newMCPHandler(ctx, services, logger), logger)
 
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
@@ -98,5 +109,17 @@ func main() {
 
 	// เริ่มเซิร์ฟเวอร์ API
 	logger.Info("API Server is running", zap.String("url", "http://"+host+":"+port))
-	e.Logger.Fatal(e.Start(host + ":" + port))
+	go func() {
+		if err := e.Start(host + ":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("API server error", zap.Error(err))
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("Shutting down API server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to shutdown API server", zap.Error(err))
+	}
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Napat/mcpserver-demo/internal/mcpserver"
+	"github.com/Napat/mcpserver-demo/internal/router"
+	"go.uber.org/zap"
+)
+
+// newMCPHandler สร้าง MCP server ที่เรียก services โดยตรง และคืน handler สำหรับ mount ที่ /mcp
+// notification ของ note://{id} ทำงานจนกว่า ctx จะถูกยกเลิกเมื่อ API server หยุดทำงาน
+func newMCPHandler(ctx context.Context, services *router.Services, logger *zap.Logger) http.Handler {
+	rateLimits, err := mcpserver.LoadRateLimitConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Failed to load MCP rate limits", zap.Error(err))
+	}
+
+	opts := []mcpserver.Option{
+		mcpserver.WithGateway(mcpserver.NewServiceGateway(services.UserService, services.NoteService, services.VisitorService, logger)),
+		mcpserver.WithExternalAuth(),
+		mcpserver.WithPolicy(mcpserver.LoadPolicyFromEnv()),
+		mcpserver.WithAuditLogger(logger),
+		// เก็บสถานะ rate limit ใน Redis เพื่อใช้ร่วมกันทุก instance
+		mcpserver.WithRateLimit(mcpserver.NewRedisRateLimitStore(services.Redis), rateLimits),
+	}
+	if mcpserver.AuditPersistenceEnabled() {
+		opts = append(opts, mcpserver.WithAuditStore(services.AuditLogs))
+	}
+	s := mcpserver.CreateServer(opts...)
+
+	// ส่ง notification ของ resource note://{id} ให้ client ที่ subscribe เมื่อบันทึกถูกแก้ไขผ่าน NoteService
+	go func() {
+		if err := mcpserver.RunNoteNotifications(ctx, s, services.NoteEvents); err != nil {
+			logger.Error("MCP note notifications stopped", zap.Error(err))
+		}
+	}()
+
+	return mcpserver.NewHTTPHandler(s)
+}
@@ -1,18 +1,170 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Napat/mcpserver-demo/internal/mcpserver"
-	"github.com/mark3labs/mcp-go/server"
+	"github.com/Napat/mcpserver-demo/internal/repository"
+	"github.com/Napat/mcpserver-demo/internal/router"
+	"github.com/Napat/mcpserver-demo/pkg/cache"
+	"github.com/Napat/mcpserver-demo/pkg/database"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	// gatewayHTTP เรียกข้อมูลผ่าน REST API ของ cmd/api
+	gatewayHTTP = "http"
+	// gatewayInProcess เรียก service layer โดยตรงโดยเชื่อมต่อ database, Redis และ MinIO เอง
+	gatewayInProcess = "inprocess"
 )
 
 func main() {
+	// ค่าเริ่มต้นมาจาก environment variables และสามารถ override ได้ด้วย flag
+	cfg, err := mcpserver.LoadTransportConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load transport config: %v", err)
+	}
+
+	policy := mcpserver.LoadPolicyFromEnv()
+
+	gatewayMode := os.Getenv("MCP_GATEWAY")
+	if gatewayMode == "" {
+		gatewayMode = gatewayHTTP
+	}
+
+	transport := flag.String("transport", string(cfg.Transport), "Transport to serve on: stdio, sse or http (env MCP_TRANSPORT)")
+	flag.StringVar(&cfg.Addr, "addr", cfg.Addr, "Listen address for sse/http transports (env MCP_LISTEN_ADDR)")
+	flag.StringVar(&cfg.BasePath, "base-path", cfg.BasePath, "Base path of the MCP endpoint (env MCP_BASE_PATH)")
+	flag.StringVar(&cfg.BaseURL, "base-url", cfg.BaseURL, "Public base URL advertised to SSE clients (env MCP_BASE_URL)")
+	flag.BoolVar(&cfg.KeepAlive, "keep-alive", cfg.KeepAlive, "Send keep-alive pings on network transports (env MCP_KEEP_ALIVE)")
+	flag.DurationVar(&cfg.KeepAliveInterval, "keep-alive-interval", cfg.KeepAliveInterval, "Interval between keep-alive pings (env MCP_KEEP_ALIVE_INTERVAL)")
+	flag.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "Graceful shutdown timeout (env MCP_SHUTDOWN_TIMEOUT)")
+	flag.StringVar(&gatewayMode, "gateway", gatewayMode, "How tools reach the data: http (REST API) or inprocess (services directly) (env MCP_GATEWAY)")
+	flag.BoolVar(&policy.ReadOnly, "read-only", policy.ReadOnly, "Only register tools that do not create, update or delete data (env MCP_READ_ONLY)")
+	flag.Parse()
+	cfg.Transport = mcpserver.Transport(*transport)
+
+	// ใช้ stderr สำหรับ log เสมอ เพราะ stdout ถูกใช้เป็นช่องทางของ protocol ในโหมด stdio
+	log.SetOutput(os.Stderr)
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	rateLimits, err := mcpserver.LoadRateLimitConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load rate limits: %v", err)
+	}
+
+	opts := []mcpserver.Option{mcpserver.WithPolicy(policy), mcpserver.WithAuditLogger(logger)}
+	var noteEvents mcpserver.NoteEventSource
+	// stdio มี client เดียวจึงเก็บสถานะ rate limit ใน memory ส่วน transport แบบ network ใช้ Redis เมื่อมี
+	var rateStore mcpserver.RateLimitStore = mcpserver.NewMemoryRateLimitStore()
+	switch gatewayMode {
+	case gatewayHTTP:
+		// โหลดชุด backend ที่อนุญาตให้ tool เรียกใช้
+		backends, err := mcpserver.LoadBackendsFromEnv()
+		if err != nil {
+			log.Fatalf("Failed to load backends: %v", err)
+		}
+		opts = append(opts, mcpserver.WithBackends(backends))
+		if redisClient := newRedisClient(); redisClient != nil {
+			noteEvents = repository.NewNoteEventRepository(redisClient)
+			if cfg.Transport != mcpserver.TransportStdio {
+				rateStore = mcpserver.NewRedisRateLimitStore(redisClient)
+			}
+		}
+		if mcpserver.AuditPersistenceEnabled() {
+			opts = append(opts, mcpserver.WithAuditStore(repository.NewAuditLogRepository(connectDatabase(logger))))
+		}
+	case gatewayInProcess:
+		services := newServices(logger)
+		opts = append(opts, mcpserver.WithGateway(mcpserver.NewServiceGateway(services.UserService, services.NoteService, services.VisitorService, logger)))
+		noteEvents = services.NoteEvents
+		if cfg.Transport != mcpserver.TransportStdio {
+			rateStore = mcpserver.NewRedisRateLimitStore(services.Redis)
+		}
+		if mcpserver.AuditPersistenceEnabled() {
+			opts = append(opts, mcpserver.WithAuditStore(services.AuditLogs))
+		}
+	default:
+		log.Fatalf("Unsupported gateway %q (supported: %s, %s)", gatewayMode, gatewayHTTP, gatewayInProcess)
+	}
+
+	opts = append(opts, mcpserver.WithRateLimit(rateStore, rateLimits))
+
 	// สร้าง MCP server จาก package mcpserver
-	s := mcpserver.CreateServer()
+	s := mcpserver.CreateServer(opts...)
+
+	// หยุดการทำงานอย่างนุ่มนวลเมื่อได้รับ SIGINT หรือ SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// ส่ง notification ให้ client ที่ subscribe บันทึกเมื่อมีการเปลี่ยนแปลง
+	if noteEvents != nil {
+		go func() {
+			if err := mcpserver.RunNoteNotifications(ctx, s, noteEvents); err != nil {
+				log.Printf("Note notifications stopped: %v", err)
+			}
+		}()
+	}
 
 	// เริ่มการทำงานของ server
-	if err := server.ServeStdio(s); err != nil {
-		fmt.Printf("Server error: %v\n", err)
+	if err := mcpserver.Serve(ctx, s, cfg); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
+
+// newRedisClient เชื่อมต่อ Redis ของ API เมื่อกำหนด REDIS_ADDR ไว้ เพื่อรับเหตุการณ์ของบันทึกและเก็บสถานะ rate limit
+// หากไม่ได้กำหนดหรือเชื่อมต่อไม่ได้ server จะทำงานต่อโดยไม่ส่ง notification และเก็บ rate limit ใน memory
+func newRedisClient() *cache.RedisClient {
+	if os.Getenv("REDIS_ADDR") == "" {
+		return nil
+	}
+
+	redisClient, err := cache.NewRedisClient()
+	if err != nil {
+		log.Printf("Warning: note notifications and shared rate limits disabled, failed to connect to Redis: %v", err)
+		return nil
+	}
+	return redisClient
+}
+
+// newServices เชื่อมต่อ dependencies แบบเดียวกับ cmd/api และสร้าง services สำหรับโหมด in-process
+func newServices(logger *zap.Logger) *router.Services {
+	return router.NewServices(connectDatabase(logger), logger)
+}
+
+// connectDatabase โหลด configs/temp/.env และเชื่อมต่อฐานข้อมูลแบบเดียวกับ cmd/api
+func connectDatabase(logger *zap.Logger) *gorm.DB {
+	// โหลดไฟล์ .env
+	if err := godotenv.Load("configs/temp/.env"); err != nil {
+		log.Printf("Warning: .env file not found or invalid: %v", err)
 	}
+
+	// log ของ GORM ต้องไปที่ stderr เพื่อไม่ให้ปนกับ protocol บน stdout
+	gormLogger := gormlogger.New(log.New(os.Stderr, "\r\n", log.LstdFlags), gormlogger.Config{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      gormlogger.Warn,
+		Colorful:      false,
+	})
+
+	// เชื่อมต่อกับฐานข้อมูล
+	db, err := database.ConnectWithLogger(gormLogger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	return db
 }
@@ -16,10 +16,21 @@ interface Note {
     updated_at: string;
 }
 
+interface NotePage {
+    notes: Note[];
+    total: number;
+    limit: number;
+    next_cursor?: string;
+    prev_cursor?: string;
+}
+
 const NotesPage = () => {
     const { isAuthenticated, logout } = useAuth();
     const router = useRouter();
     const [notes, setNotes] = useState<Note[]>([]);
+    const [total, setTotal] = useState(0);
+    const [nextCursor, setNextCursor] = useState<string | undefined>();
+    const [loadingMore, setLoadingMore] = useState(false);
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState<string | null>(null);
 
@@ -29,14 +40,23 @@ const NotesPage = () => {
         router.push('/login');
     }, [logout, router]);
 
-    const fetchNotes = useCallback(async () => {
-        setLoading(true);
+    // ดึงบันทึกหนึ่งหน้า ถ้ามี cursor จะต่อท้ายรายการเดิม
+    const fetchNotes = useCallback(async (cursor?: string) => {
+        if (cursor) {
+            setLoadingMore(true);
+        } else {
+            setLoading(true);
+        }
         setError(null);
         try {
             console.log('กำลังดึงข้อมูลบันทึก...');
-            const response = await apiClient.get<Note[]>('/api/notes');
+            const response = await apiClient.get<NotePage>('/api/notes', {
+                params: cursor ? { cursor } : undefined,
+            });
             console.log('ได้รับข้อมูลบันทึกแล้ว:', response.data);
-            setNotes(response.data);
+            setNotes(prev => (cursor ? [...prev, ...response.data.notes] : response.data.notes));
+            setTotal(response.data.total);
+            setNextCursor(response.data.next_cursor);
         } catch (err: unknown) {
             console.error("ไม่สามารถดึงข้อมูลบันทึกได้:", err);
             let errorMessage = 'ไม่สามารถโหลดบันทึกได้ กรุณาลองอีกครั้ง';
@@ -54,6 +74,7 @@ const NotesPage = () => {
             setError(errorMessage);
         } finally {
             setLoading(false);
+            setLoadingMore(false);
         }
     }, [handleLogout]);
 
@@ -76,6 +97,7 @@ const NotesPage = () => {
         try {
             await apiClient.delete(`/api/notes/${id}`);
             setNotes(notes.filter(note => note.id !== id)); // ลบบันทึกออกจาก state
+            setTotal(count => count - 1);
         } catch (err: unknown) {
             console.error("ไม่สามารถลบบันทึกได้:", err);
             let errorMessage = 'ไม่สามารถลบบันทึกได้ กรุณาลองอีกครั้ง';
@@ -106,7 +128,7 @@ const NotesPage = () => {
     return (
         <div className="container mx-auto p-4 bg-gray-800 min-h-screen">
             <div className="flex justify-between items-center mb-6">
-                <h1 className="text-2xl font-bold text-gray-100">บันทึกของฉัน</h1>
+                <h1 className="text-2xl font-bold text-gray-100">บันทึกของฉัน ({total})</h1>
                 <div>
                     <Link
                         href="/notes/new"
@@ -149,6 +171,18 @@ const NotesPage = () => {
                     ))}
                 </div>
             )}
+
+            {nextCursor && (
+                <div className="text-center mt-6">
+                    <button
+                        onClick={() => fetchNotes(nextCursor)}
+                        disabled={loadingMore}
+                        className="bg-gray-700 hover:bg-gray-600 text-gray-300 px-4 py-2 rounded disabled:opacity-50"
+                    >
+                        {loadingMore ? 'กำลังโหลด...' : 'โหลดเพิ่มเติม'}
+                    </button>
+                </div>
+            )}
         </div>
     );
 };
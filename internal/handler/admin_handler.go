@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Napat/mcpserver-demo/internal/service"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/Napat/mcpserver-demo/pkg/middleware"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// UpdateRoleRequest is a data structure for changing the roles of a user
+type UpdateRoleRequest struct {
+	Role models.UserRole `json:"role" validate:"required"`
+}
+
+// AdminHandler handles user management operations for admins
+type AdminHandler struct {
+	userService service.IUserService
+	logger      *zap.Logger
+}
+
+// NewAdminHandler creates a new instance of AdminHandler
+func NewAdminHandler(userService service.IUserService, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// ListUsers retrieves all users, including inactive ones
+func (h *AdminHandler) ListUsers(c echo.Context) error {
+	users, err := h.userService.ListUsers()
+	if err != nil {
+		h.logger.Error("Failed to list users", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list users")
+	}
+
+	return c.JSON(http.StatusOK, users)
+}
+
+// DeactivateUser deactivates a user
+func (h *AdminHandler) DeactivateUser(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := h.userService.DeactivateUser(uint(userID), middleware.GetUserIDFromToken(c), middleware.GetUserRoleFromToken(c))
+	if err != nil {
+		return h.userError(err, "Failed to deactivate user")
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// UpdateUserRole replaces the roles of a user
+func (h *AdminHandler) UpdateUserRole(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	req := new(UpdateRoleRequest)
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := h.userService.ChangeRole(uint(userID), req.Role, middleware.GetUserIDFromToken(c), middleware.GetUserRoleFromToken(c))
+	if err != nil {
+		return h.userError(err, "Failed to change user role")
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// GetUserLoginHistory retrieves the login history of any user
+func (h *AdminHandler) GetUserLoginHistory(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	limit := 10
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	history, err := h.userService.GetLoginHistory(uint(userID), limit)
+	if err != nil {
+		h.logger.Error("Failed to get login history", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get login history")
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// userError maps user service errors to HTTP errors
+func (h *AdminHandler) userError(err error, message string) error {
+	switch err.Error() {
+	case "user not found":
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case "user is inactive":
+		return echo.NewHTTPError(http.StatusConflict, "User is inactive")
+	case "invalid role", "cannot deactivate your own account", "cannot remove your own admin roles":
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case "cannot remove the last super admin":
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case "only super admins can manage super admins":
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return echo.NewHTTPError(http.StatusInternalServerError, message)
+}
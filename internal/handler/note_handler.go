@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Napat/mcpserver-demo/internal/repository"
 	"github.com/Napat/mcpserver-demo/internal/service"
 	"github.com/Napat/mcpserver-demo/models"
 	"github.com/Napat/mcpserver-demo/pkg/middleware"
@@ -24,6 +28,17 @@ type UpdateNoteRequest struct {
 	Content string `json:"content" validate:"required"`
 }
 
+// MoveNoteRequest is a data structure for moving a note to a notebook
+// A nil NotebookID takes the note out of its notebook
+type MoveNoteRequest struct {
+	NotebookID *uint `json:"notebook_id"`
+}
+
+// AddTagsRequest is a data structure for adding tags to a note
+type AddTagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1"`
+}
+
 // NoteHandler handles note operations
 type NoteHandler struct {
 	noteService service.INoteService
@@ -38,17 +53,146 @@ func NewNoteHandler(noteService service.INoteService, logger *zap.Logger) *NoteH
 	}
 }
 
-// GetAllNotes retrieves all notes for a user
+// GetAllNotes retrieves a page of a user's notes
+// Query parameters: limit, cursor, sort (created_at, updated_at, title), order (asc, desc),
+// title, title_prefix, created_after, created_before, updated_after, updated_before,
+// tag (repeatable, notes must have every tag) and notebook_id (a notebook ID or "none")
 func (h *NoteHandler) GetAllNotes(c echo.Context) error {
 	userID := middleware.GetUserIDFromToken(c)
 
-	notes, err := h.noteService.GetAllByUserID(userID)
+	params, err := parseNoteListParams(c)
 	if err != nil {
+		return err
+	}
+
+	page, err := h.noteService.ListByUserID(userID, params)
+	if err != nil {
+		switch err.Error() {
+		case "invalid cursor", "cursor does not match the sort order", "invalid tag name":
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		h.logger.Error("Failed to get notes", zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get notes")
 	}
 
-	return c.JSON(http.StatusOK, notes)
+	return c.JSON(http.StatusOK, page)
+}
+
+// parseNoteListParams reads the paging, sorting and filter query parameters of GetAllNotes
+func parseNoteListParams(c echo.Context) (service.NoteListParams, error) {
+	params := service.NoteListParams{
+		SortBy:     repository.NoteSortCreatedAt,
+		Descending: true,
+		Limit:      service.DefaultNotePageSize,
+		Cursor:     c.QueryParam("cursor"),
+		Filter: repository.NoteFilter{
+			TitleContains: strings.TrimSpace(c.QueryParam("title")),
+			TitlePrefix:   strings.TrimSpace(c.QueryParam("title_prefix")),
+			Tags:          c.QueryParams()["tag"],
+		},
+	}
+
+	switch notebookID := c.QueryParam("notebook_id"); notebookID {
+	case "":
+	case "none":
+		params.Filter.WithoutNotebook = true
+	default:
+		id, err := strconv.ParseUint(notebookID, 10, 32)
+		if err != nil {
+			return params, echo.NewHTTPError(http.StatusBadRequest, "notebook_id must be a notebook ID or none")
+		}
+		notebook := uint(id)
+		params.Filter.NotebookID = &notebook
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > service.MaxNotePageSize {
+			return params, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", service.MaxNotePageSize))
+		}
+		params.Limit = limit
+	}
+
+	switch sort := repository.NoteSortField(c.QueryParam("sort")); sort {
+	case "":
+	case repository.NoteSortCreatedAt, repository.NoteSortUpdatedAt, repository.NoteSortTitle:
+		params.SortBy = sort
+	default:
+		return params, echo.NewHTTPError(http.StatusBadRequest, "sort must be one of created_at, updated_at, title")
+	}
+
+	switch c.QueryParam("order") {
+	case "", "desc":
+	case "asc":
+		params.Descending = false
+	default:
+		return params, echo.NewHTTPError(http.StatusBadRequest, "order must be asc or desc")
+	}
+
+	bounds := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"created_after", &params.Filter.CreatedAfter},
+		{"created_before", &params.Filter.CreatedBefore},
+		{"updated_after", &params.Filter.UpdatedAfter},
+		{"updated_before", &params.Filter.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		value := c.QueryParam(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := parseTimeParam(value)
+		if err != nil {
+			return params, echo.NewHTTPError(http.StatusBadRequest, bound.name+" must be an RFC 3339 time or a YYYY-MM-DD date")
+		}
+		*bound.target = &t
+	}
+
+	return params, nil
+}
+
+// parseTimeParam parses an RFC 3339 time or a date (midnight UTC)
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// SearchNotes searches a user's notes by title and content
+// Query parameters: q (required) and limit
+func (h *NoteHandler) SearchNotes(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "q is required")
+	}
+
+	limit := service.DefaultNoteSearchLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > service.MaxNoteSearchLimit {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", service.MaxNoteSearchLimit))
+		}
+		limit = parsedLimit
+	}
+
+	results, err := h.noteService.Search(userID, query, limit)
+	if err != nil {
+		if err.Error() == "search query is required" {
+			return echo.NewHTTPError(http.StatusBadRequest, "q is required")
+		}
+		h.logger.Error("Failed to search notes", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search notes")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"query":   query,
+		"results": results,
+	})
 }
 
 // GetNote retrieves a note by ID
@@ -64,7 +208,7 @@ func (h *NoteHandler) GetNote(c echo.Context) error {
 		if err.Error() == "unauthorized access to note" {
 			return echo.NewHTTPError(http.StatusForbidden, "Access denied")
 		}
-		if err == gorm.ErrRecordNotFound {
+		if err == gorm.ErrRecordNotFound || err.Error() == "note not found" {
 			return echo.NewHTTPError(http.StatusNotFound, "Note not found")
 		}
 		h.logger.Error("Failed to get note", zap.Error(err))
@@ -130,7 +274,7 @@ func (h *NoteHandler) UpdateNote(c echo.Context) error {
 		if err.Error() == "unauthorized access to note" {
 			return echo.NewHTTPError(http.StatusForbidden, "Access denied")
 		}
-		if err == gorm.ErrRecordNotFound {
+		if err == gorm.ErrRecordNotFound || err.Error() == "note not found" {
 			return echo.NewHTTPError(http.StatusNotFound, "Note not found")
 		}
 		h.logger.Error("Failed to update note", zap.Error(err))
@@ -153,7 +297,7 @@ func (h *NoteHandler) DeleteNote(c echo.Context) error {
 		if err.Error() == "unauthorized access to note" {
 			return echo.NewHTTPError(http.StatusForbidden, "Access denied")
 		}
-		if err == gorm.ErrRecordNotFound {
+		if err == gorm.ErrRecordNotFound || err.Error() == "note not found" {
 			return echo.NewHTTPError(http.StatusNotFound, "Note not found")
 		}
 		h.logger.Error("Failed to delete note", zap.Error(err))
@@ -162,3 +306,203 @@ func (h *NoteHandler) DeleteNote(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// MoveNote moves a note to a notebook, or out of its notebook when notebook_id is null
+func (h *NoteHandler) MoveNote(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+
+	req := new(MoveNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+
+	note, err := h.noteService.MoveToNotebook(uint(noteID), userID, req.NotebookID)
+	if err != nil {
+		return h.noteError(err, "Failed to move note")
+	}
+
+	return c.JSON(http.StatusOK, note)
+}
+
+// AddTags adds tags to a note by name, creating tags that do not exist yet
+func (h *NoteHandler) AddTags(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+
+	req := new(AddTagsRequest)
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	note, err := h.noteService.AddTags(uint(noteID), userID, req.Tags)
+	if err != nil {
+		return h.noteError(err, "Failed to add tags")
+	}
+
+	return c.JSON(http.StatusOK, note)
+}
+
+// RemoveTag removes a tag from a note
+func (h *NoteHandler) RemoveTag(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+	tagID, err := strconv.ParseUint(c.Param("tagId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tag ID")
+	}
+
+	note, err := h.noteService.RemoveTag(uint(noteID), userID, uint(tagID))
+	if err != nil {
+		return h.noteError(err, "Failed to remove tag")
+	}
+
+	return c.JSON(http.StatusOK, note)
+}
+
+// GetTags retrieves all tags of a user
+func (h *NoteHandler) GetTags(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+
+	tags, err := h.noteService.GetTags(userID)
+	if err != nil {
+		h.logger.Error("Failed to get tags", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tags")
+	}
+
+	return c.JSON(http.StatusOK, tags)
+}
+
+// DeleteTag deletes a tag and removes it from every note
+func (h *NoteHandler) DeleteTag(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tag ID")
+	}
+
+	if err := h.noteService.DeleteTag(uint(tagID), userID); err != nil {
+		return h.noteError(err, "Failed to delete tag")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetRevisions retrieves every revision of a note, newest first
+func (h *NoteHandler) GetRevisions(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+
+	revisions, err := h.noteService.ListRevisions(uint(noteID), userID)
+	if err != nil {
+		return h.noteError(err, "Failed to get revisions")
+	}
+
+	return c.JSON(http.StatusOK, revisions)
+}
+
+// GetRevision retrieves one revision of a note
+func (h *NoteHandler) GetRevision(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision")
+	}
+
+	rev, err := h.noteService.GetRevision(uint(noteID), userID, revision)
+	if err != nil {
+		return h.noteError(err, "Failed to get revision")
+	}
+
+	return c.JSON(http.StatusOK, rev)
+}
+
+// DiffRevisions shows the line-level difference between two revisions of a note
+// Query parameters: from (default: the revision before to), to (default: the latest revision)
+func (h *NoteHandler) DiffRevisions(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+
+	var from, to int
+	if value := c.QueryParam("from"); value != "" {
+		if from, err = strconv.Atoi(value); err != nil || from < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid from revision")
+		}
+	}
+	if value := c.QueryParam("to"); value != "" {
+		if to, err = strconv.Atoi(value); err != nil || to < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid to revision")
+		}
+	}
+
+	diff, err := h.noteService.DiffRevisions(uint(noteID), userID, from, to)
+	if err != nil {
+		return h.noteError(err, "Failed to diff revisions")
+	}
+
+	return c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision restores the title and content of an old revision as a new revision
+func (h *NoteHandler) RestoreRevision(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision")
+	}
+
+	note, err := h.noteService.RestoreRevision(uint(noteID), userID, revision)
+	if err != nil {
+		return h.noteError(err, "Failed to restore revision")
+	}
+
+	return c.JSON(http.StatusOK, note)
+}
+
+// noteError maps note, tag, notebook and revision errors of the note service to HTTP errors
+func (h *NoteHandler) noteError(err error, message string) error {
+	switch err.Error() {
+	case "note not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Note not found")
+	case "tag not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Tag not found")
+	case "notebook not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Notebook not found")
+	case "revision not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Revision not found")
+	case "unauthorized access to note", "unauthorized access to tag", "unauthorized access to notebook":
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case "invalid tag name", "invalid revision":
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return echo.NewHTTPError(http.StatusInternalServerError, message)
+}
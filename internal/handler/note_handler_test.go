@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/internal/repository"
+	"github.com/Napat/mcpserver-demo/internal/service"
+	"github.com/Napat/mcpserver-demo/internal/service/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// newNoteListContext creates the echo context of GET /api/notes with the query string of a test case
+func newNoteListContext(query string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/api/notes?"+query, nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("user", jwt.MapClaims{"user_id": float64(1)})
+	return c
+}
+
+func TestParseNoteListParams(t *testing.T) {
+	date := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	instant := time.Date(2026, 10, 1, 9, 30, 0, 0, time.FixedZone("", 7*60*60))
+	notebook := uint(7)
+	defaults := service.NoteListParams{SortBy: repository.NoteSortCreatedAt, Descending: true, Limit: service.DefaultNotePageSize}
+
+	cases := []struct {
+		name       string
+		query      string
+		want       func(p *service.NoteListParams)
+		wantStatus int
+	}{
+		{name: "defaults", query: ""},
+		{name: "cursor is passed through", query: "cursor=abc_-123", want: func(p *service.NoteListParams) { p.Cursor = "abc_-123" }},
+		{name: "limit", query: "limit=5", want: func(p *service.NoteListParams) { p.Limit = 5 }},
+		{name: "limit at the maximum", query: "limit=100", want: func(p *service.NoteListParams) { p.Limit = service.MaxNotePageSize }},
+		{name: "limit zero", query: "limit=0", wantStatus: http.StatusBadRequest},
+		{name: "limit too large", query: "limit=101", wantStatus: http.StatusBadRequest},
+		{name: "limit not a number", query: "limit=ten", wantStatus: http.StatusBadRequest},
+		{name: "sort title ascending", query: "sort=title&order=asc", want: func(p *service.NoteListParams) { p.SortBy, p.Descending = repository.NoteSortTitle, false }},
+		{name: "sort updated_at", query: "sort=updated_at", want: func(p *service.NoteListParams) { p.SortBy = repository.NoteSortUpdatedAt }},
+		{name: "unknown sort", query: "sort=id", wantStatus: http.StatusBadRequest},
+		{name: "unknown order", query: "order=up", wantStatus: http.StatusBadRequest},
+		{name: "title is trimmed", query: "title=+shop+", want: func(p *service.NoteListParams) { p.Filter.TitleContains = "shop" }},
+		{name: "title prefix", query: "title_prefix=Shop", want: func(p *service.NoteListParams) { p.Filter.TitlePrefix = "Shop" }},
+		{name: "repeated tags", query: "tag=work&tag=urgent", want: func(p *service.NoteListParams) { p.Filter.Tags = []string{"work", "urgent"} }},
+		{name: "notebook", query: "notebook_id=7", want: func(p *service.NoteListParams) { p.Filter.NotebookID = &notebook }},
+		{name: "without notebook", query: "notebook_id=none", want: func(p *service.NoteListParams) { p.Filter.WithoutNotebook = true }},
+		{name: "invalid notebook", query: "notebook_id=inbox", wantStatus: http.StatusBadRequest},
+		{name: "date bound", query: "created_after=2026-10-01", want: func(p *service.NoteListParams) { p.Filter.CreatedAfter = &date }},
+		{name: "RFC 3339 bound", query: "updated_before=2026-10-01T09:30:00%2B07:00", want: func(p *service.NoteListParams) { p.Filter.UpdatedBefore = &instant }},
+		{name: "invalid bound", query: "created_before=yesterday", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := parseNoteListParams(newNoteListContext(tc.query))
+			if tc.wantStatus != 0 {
+				var httpErr *echo.HTTPError
+				if !errors.As(err, &httpErr) || httpErr.Code != tc.wantStatus {
+					t.Fatalf("parseNoteListParams error = %v, want status %d", err, tc.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseNoteListParams failed: %v", err)
+			}
+
+			want := defaults
+			if tc.want != nil {
+				tc.want(&want)
+			}
+			if !reflect.DeepEqual(params, want) {
+				t.Errorf("parseNoteListParams = %+v, want %+v", params, want)
+			}
+		})
+	}
+}
+
+func TestGetAllNotesCursorErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "invalid cursor", err: errors.New("invalid cursor"), wantStatus: http.StatusBadRequest},
+		{name: "foreign cursor", err: errors.New("cursor does not match the sort order"), wantStatus: http.StatusBadRequest},
+		{name: "repository failure", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			noteService := mocks.NewMockINoteService(ctrl)
+			noteService.EXPECT().ListByUserID(uint(1), gomock.Any()).Return(nil, tc.err)
+
+			err := NewNoteHandler(noteService, zap.NewNop()).GetAllNotes(newNoteListContext("cursor=abc"))
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != tc.wantStatus {
+				t.Errorf("GetAllNotes error = %v, want status %d", err, tc.wantStatus)
+			}
+		})
+	}
+}
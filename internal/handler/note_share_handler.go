@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Napat/mcpserver-demo/internal/service"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/Napat/mcpserver-demo/pkg/middleware"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// ShareNoteRequest is a data structure for sharing a note with another user
+type ShareNoteRequest struct {
+	Email      string                `json:"email" validate:"required,email"`
+	Permission models.NotePermission `json:"permission" validate:"required"`
+}
+
+// NoteShareHandler handles note sharing operations
+type NoteShareHandler struct {
+	shareService service.INoteShareService
+	logger       *zap.Logger
+}
+
+// NewNoteShareHandler creates a new instance of NoteShareHandler
+func NewNoteShareHandler(shareService service.INoteShareService, logger *zap.Logger) *NoteShareHandler {
+	return &NoteShareHandler{
+		shareService: shareService,
+		logger:       logger,
+	}
+}
+
+// GetSharedWithMe retrieves the notes other users shared with the caller
+func (h *NoteShareHandler) GetSharedWithMe(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+
+	notes, err := h.shareService.SharedWithMe(userID)
+	if err != nil {
+		h.logger.Error("Failed to get shared notes", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get shared notes")
+	}
+
+	return c.JSON(http.StatusOK, notes)
+}
+
+// GetShares retrieves everyone a note is shared with
+func (h *NoteShareHandler) GetShares(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+
+	shares, err := h.shareService.ListShares(uint(noteID), userID)
+	if err != nil {
+		return h.shareError(err, "Failed to get note shares")
+	}
+
+	return c.JSON(http.StatusOK, shares)
+}
+
+// ShareNote shares a note with another user by email, or changes their permission
+func (h *NoteShareHandler) ShareNote(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+
+	req := new(ShareNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	share, err := h.shareService.Share(uint(noteID), userID, req.Email, req.Permission)
+	if err != nil {
+		return h.shareError(err, "Failed to share note")
+	}
+
+	return c.JSON(http.StatusOK, share)
+}
+
+// UnshareNote removes a user's access to a note
+func (h *NoteShareHandler) UnshareNote(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	noteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid note ID")
+	}
+	shareUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.shareService.Unshare(uint(noteID), userID, uint(shareUserID)); err != nil {
+		return h.shareError(err, "Failed to unshare note")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// shareError maps note share service errors to HTTP errors
+func (h *NoteShareHandler) shareError(err error, message string) error {
+	switch err.Error() {
+	case "note not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Note not found")
+	case "user not found":
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case "share not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Share not found")
+	case "unauthorized access to note":
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case "invalid permission", "cannot share a note with its owner":
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return echo.NewHTTPError(http.StatusInternalServerError, message)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Napat/mcpserver-demo/internal/service"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/Napat/mcpserver-demo/pkg/middleware"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// NotebookRequest is a data structure for creating, renaming or moving a notebook
+// A nil ParentID places the notebook at the top level
+type NotebookRequest struct {
+	Name     string `json:"name" validate:"required"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// NotebookHandler handles notebook operations
+type NotebookHandler struct {
+	notebookService service.INotebookService
+	logger          *zap.Logger
+}
+
+// NewNotebookHandler creates a new instance of NotebookHandler
+func NewNotebookHandler(notebookService service.INotebookService, logger *zap.Logger) *NotebookHandler {
+	return &NotebookHandler{
+		notebookService: notebookService,
+		logger:          logger,
+	}
+}
+
+// GetAllNotebooks retrieves all notebooks of a user as a flat list linked by parent_id
+func (h *NotebookHandler) GetAllNotebooks(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+
+	notebooks, err := h.notebookService.GetAllByUserID(userID)
+	if err != nil {
+		h.logger.Error("Failed to get notebooks", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get notebooks")
+	}
+
+	return c.JSON(http.StatusOK, notebooks)
+}
+
+// CreateNotebook creates a new notebook
+func (h *NotebookHandler) CreateNotebook(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+
+	req := new(NotebookRequest)
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	notebook := models.Notebook{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		UserID:   userID,
+	}
+
+	if err := h.notebookService.Create(&notebook); err != nil {
+		return h.notebookError(err, "Failed to create notebook")
+	}
+
+	return c.JSON(http.StatusCreated, notebook)
+}
+
+// UpdateNotebook renames a notebook or moves it under another parent
+func (h *NotebookHandler) UpdateNotebook(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	notebookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid notebook ID")
+	}
+
+	req := new(NotebookRequest)
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request format")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	notebook := &models.Notebook{
+		ID:       uint(notebookID),
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		UserID:   userID,
+	}
+
+	if err := h.notebookService.Update(notebook, userID); err != nil {
+		return h.notebookError(err, "Failed to update notebook")
+	}
+
+	return c.JSON(http.StatusOK, notebook)
+}
+
+// DeleteNotebook deletes a notebook; its notes are kept without a notebook
+func (h *NotebookHandler) DeleteNotebook(c echo.Context) error {
+	userID := middleware.GetUserIDFromToken(c)
+	notebookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid notebook ID")
+	}
+
+	if err := h.notebookService.Delete(uint(notebookID), userID); err != nil {
+		return h.notebookError(err, "Failed to delete notebook")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// notebookError maps notebook service errors to HTTP errors
+func (h *NotebookHandler) notebookError(err error, message string) error {
+	switch err.Error() {
+	case "notebook not found":
+		return echo.NewHTTPError(http.StatusNotFound, "Notebook not found")
+	case "unauthorized access to notebook":
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case "invalid notebook name", "notebook cannot be moved into itself":
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case "notebook has child notebooks":
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return echo.NewHTTPError(http.StatusInternalServerError, message)
+}
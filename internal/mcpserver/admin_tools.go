@@ -0,0 +1,284 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// adminToolNames คือ tool ที่แสดงใน tools/list เฉพาะ session ที่มีบทบาท Admin หรือ Super Admin
+var adminToolNames = map[string]bool{
+	"list_users":             true,
+	"deactivate_user":        true,
+	"change_user_role":       true,
+	"get_user_login_history": true,
+}
+
+// สร้าง Tool สำหรับดูรายชื่อผู้ใช้ทั้งหมด (admin)
+func CreateListUsersTool() mcp.Tool {
+	return mcp.NewTool("list_users",
+		mcp.WithDescription("List all users with their roles and status, including deactivated users (admin only)"),
+		readOnlyTool(),
+	)
+}
+
+// ListUsersHandler เป็นฟังก์ชันสำหรับดึงรายชื่อผู้ใช้ทั้งหมด
+func (s *Server) ListUsersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.adminCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.gateway.ListUsers(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Found %d users", len(users))
+	for _, user := range users {
+		fmt.Fprintf(&text, "\n- [%d] %s (%s %s) roles: %s", user.ID, user.Email, user.FirstName, user.LastName, strings.Join(user.Roles, ", "))
+		if !user.Active {
+			text.WriteString(" [deactivated]")
+		}
+	}
+
+	return toolResult(text.String(), map[string]interface{}{"users": users})
+}
+
+// สร้าง Tool สำหรับปิดการใช้งานผู้ใช้ (admin)
+func CreateDeactivateUserTool() mcp.Tool {
+	return mcp.NewTool("deactivate_user",
+		mcp.WithDescription("Deactivate a user so they can no longer log in (admin only, cannot deactivate yourself)"),
+		destructiveTool(false),
+		mcp.WithNumber("user_id",
+			mcp.Required(),
+			mcp.Min(1),
+			mcp.Description("ID of the user to deactivate"),
+		),
+	)
+}
+
+// DeactivateUserHandler เป็นฟังก์ชันสำหรับปิดการใช้งานผู้ใช้
+func (s *Server) DeactivateUserHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.adminCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := userIDFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.gateway.DeactivateUser(ctx, creds, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult(fmt.Sprintf("User %d (%s) deactivated", user.ID, user.Email), user)
+}
+
+// สร้าง Tool สำหรับเปลี่ยนบทบาทของผู้ใช้ (admin)
+func CreateChangeUserRoleTool() mcp.Tool {
+	return mcp.NewTool("change_user_role",
+		mcp.WithDescription("Replace the roles of a user (admin only; only super admins can grant or revoke Super Admin)"),
+		destructiveTool(false),
+		mcp.WithNumber("user_id",
+			mcp.Required(),
+			mcp.Min(1),
+			mcp.Description("ID of the user to change"),
+		),
+		mcp.WithArray("roles",
+			mcp.Required(),
+			mcp.MinItems(1),
+			mcp.WithStringEnumItems(roleNames()),
+			mcp.Description("The complete new set of roles for the user"),
+		),
+	)
+}
+
+// ChangeUserRoleHandler เป็นฟังก์ชันสำหรับเปลี่ยนบทบาทของผู้ใช้
+func (s *Server) ChangeUserRoleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.adminCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := userIDFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := roleFromNames(request.GetStringSlice("roles", nil))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.gateway.ChangeUserRole(ctx, creds, userID, uint8(role))
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult(fmt.Sprintf("User %d (%s) now has roles: %s", user.ID, user.Email, strings.Join(user.Roles, ", ")), user)
+}
+
+// สร้าง Tool สำหรับดูประวัติการเข้าสู่ระบบของผู้ใช้ใดก็ได้ (admin)
+func CreateGetUserLoginHistoryTool() mcp.Tool {
+	return mcp.NewTool("get_user_login_history",
+		mcp.WithDescription("Get the recent logins of any user, newest first (admin only)"),
+		readOnlyTool(),
+		mcp.WithNumber("user_id",
+			mcp.Required(),
+			mcp.Min(1),
+			mcp.Description("ID of the user"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(maxLoginHistoryLimit),
+			mcp.Description(fmt.Sprintf("Maximum number of entries to return (default %d)", defaultLoginHistoryLimit)),
+		),
+	)
+}
+
+// GetUserLoginHistoryHandler เป็นฟังก์ชันสำหรับดึงประวัติการเข้าสู่ระบบของผู้ใช้ที่ระบุ
+func (s *Server) GetUserLoginHistoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.adminCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := userIDFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := request.GetInt("limit", defaultLoginHistoryLimit)
+	if limit < 1 || limit > maxLoginHistoryLimit {
+		return nil, validationError("limit must be between 1 and %d", maxLoginHistoryLimit)
+	}
+
+	history, err := s.gateway.GetUserLoginHistory(ctx, creds, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Found %d logins for user %d", len(history), userID)
+	for _, record := range history {
+		fmt.Fprintf(&text, "\n- %s from %s (%s)", record.LoginTime.Format(time.RFC3339), record.IPAddress, record.UserAgent)
+	}
+
+	return toolResult(text.String(), map[string]interface{}{"user_id": userID, "logins": history})
+}
+
+// isAdmin ตรวจบทบาทของผู้ใช้ตามเงื่อนไขเดียวกับ middleware.AdminMiddleware
+func isAdmin(creds *Credentials) bool {
+	if creds == nil {
+		return false
+	}
+	role := models.UserRole(creds.User.Role)
+	return role.HasRole(models.RoleAdmin) || role.HasRole(models.RoleSuperAdmin)
+}
+
+// requireAdmin คืน error เมื่อผู้ใช้ไม่ได้ล็อกอินหรือไม่มีบทบาท admin
+func requireAdmin(creds *Credentials) error {
+	if creds == nil {
+		return ErrNotLoggedIn
+	}
+	if !isAdmin(creds) {
+		return newToolError(ErrCodeUnauthorized, "admin role required")
+	}
+	return nil
+}
+
+// adminCredentials ดึง credentials ของ session และตรวจว่าเป็น admin
+func (s *Server) adminCredentials(ctx context.Context) (*Credentials, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAdmin(creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// adminToolFilter ซ่อน admin tools จาก tools/list ของ session ที่ไม่ใช่ admin
+func (s *Server) adminToolFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	if creds, err := s.credentialsFromContext(ctx); err == nil && isAdmin(creds) {
+		return tools
+	}
+
+	filtered := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if !adminToolNames[tool.Name] {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// notifyToolListChanged แจ้ง client ของ session ปัจจุบันให้ขอ tools/list ใหม่เมื่อสิทธิ์ admin เปลี่ยน
+func notifyToolListChanged(ctx context.Context, before, after *Credentials) {
+	if isAdmin(before) == isAdmin(after) {
+		return
+	}
+
+	if s := server.ServerFromContext(ctx); s != nil {
+		_ = s.SendNotificationToClient(ctx, mcp.MethodNotificationToolsListChanged, nil)
+	}
+}
+
+// userIDFromRequest อ่าน user_id จาก argument ของ tool
+func userIDFromRequest(request mcp.CallToolRequest) (uint, error) {
+	id := request.GetInt("user_id", 0)
+	if id < 1 {
+		return 0, validationError("user_id must be a positive number")
+	}
+	return uint(id), nil
+}
+
+// roleNames คืนชื่อบทบาททั้งหมดเรียงตามลำดับ bit
+func roleNames() []string {
+	roles := make([]models.UserRole, 0, len(models.RoleNames))
+	for role := range models.RoleNames {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, models.RoleNames[role])
+	}
+	return names
+}
+
+// roleFromNames รวมชื่อบทบาทเป็นค่า UserRole
+func roleFromNames(names []string) (models.UserRole, error) {
+	var role models.UserRole
+	for _, name := range names {
+		found := false
+		for r, roleName := range models.RoleNames {
+			if strings.EqualFold(roleName, strings.TrimSpace(name)) {
+				role.AddRole(r)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, validationError("unknown role %q (allowed: %s)", name, strings.Join(roleNames(), ", "))
+		}
+	}
+
+	if role == 0 {
+		return 0, validationError("at least one role is required")
+	}
+	return role, nil
+}
@@ -0,0 +1,180 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// maxAuditStringLength คือความยาวสูงสุดของ argument แบบข้อความที่เก็บใน audit log
+// ข้อความที่ยาวกว่านี้ เช่นรูปภาพแบบ base64 จะถูกแทนด้วยขนาดของข้อมูล
+const maxAuditStringLength = 256
+
+// sensitiveArgumentNames คือคำในชื่อ argument ที่ต้องปิดค่าก่อนบันทึก
+var sensitiveArgumentNames = []string{"password", "token", "secret", "api_key", "authorization"}
+
+// AuditStore คือที่เก็บ audit log ถาวร เช่น repository.AuditLogRepository
+type AuditStore interface {
+	Create(log *models.AuditLog) error
+}
+
+// AuditPersistenceEnabled ตรวจ MCP_AUDIT_PERSIST ว่าให้บันทึก audit log ลงฐานข้อมูลหรือไม่ (ค่าเริ่มต้น false)
+func AuditPersistenceEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("MCP_AUDIT_PERSIST"))
+	return enabled
+}
+
+// auditMiddleware บันทึกการเรียก tool ทุกครั้งผ่าน zap และ store (ถ้ามี)
+// ต้องอยู่ใน toolErrorMiddleware เพื่อให้เห็น error เดิมของ handler
+func (s *Server) auditMiddleware(logger *zap.Logger, store AuditStore) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// ใช้ผู้ใช้ก่อนเรียก tool เพื่อให้ logout ยังระบุผู้ใช้ได้ และใช้ผู้ใช้หลังเรียกสำหรับ login
+			creds, _ := s.credentialsFromContext(ctx)
+			start := time.Now()
+
+			result, err := next(ctx, request)
+
+			if creds == nil {
+				creds, _ = s.credentialsFromContext(ctx)
+			}
+			entry := newAuditLog(ctx, request, creds, time.Since(start), result, err)
+			recordAudit(logger, store, entry)
+
+			return result, err
+		}
+	}
+}
+
+// newAuditLog สร้าง audit log ของการเรียก tool หนึ่งครั้ง
+func newAuditLog(ctx context.Context, request mcp.CallToolRequest, creds *Credentials, duration time.Duration, result *mcp.CallToolResult, err error) *models.AuditLog {
+	entry := &models.AuditLog{
+		Tool:       request.Params.Name,
+		Outcome:    models.AuditOutcomeSuccess,
+		DurationMs: duration.Milliseconds(),
+		CreatedAt:  time.Now(),
+	}
+
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		entry.SessionID = session.SessionID()
+		if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+			if info := withInfo.GetClientInfo(); info.Name != "" {
+				entry.Client = strings.TrimSuffix(info.Name+"/"+info.Version, "/")
+			}
+		}
+	}
+
+	if creds != nil {
+		userID := uint(creds.User.ID)
+		entry.UserID = &userID
+		entry.Backend = creds.Backend.Name
+	}
+
+	arguments, marshalErr := json.Marshal(redactArguments(request.GetArguments()))
+	if marshalErr != nil {
+		arguments = []byte("null")
+	}
+	entry.Arguments = string(arguments)
+
+	switch {
+	case err != nil:
+		toolErr := toToolError(err)
+		entry.Outcome = models.AuditOutcomeError
+		entry.ErrorCode = toolErr.Code
+		entry.Error = toolErr.Message
+	case result != nil && result.IsError:
+		entry.Outcome = models.AuditOutcomeError
+		if len(result.Content) > 0 {
+			if text, ok := mcp.AsTextContent(result.Content[0]); ok {
+				entry.Error = text.Text
+			}
+		}
+	}
+
+	return entry
+}
+
+// recordAudit เขียน audit log ลง zap และ store โดยความผิดพลาดของ store ไม่กระทบผลลัพธ์ของ tool
+func recordAudit(logger *zap.Logger, store AuditStore, entry *models.AuditLog) {
+	fields := []zap.Field{
+		zap.String("tool", entry.Tool),
+		zap.String("session_id", entry.SessionID),
+		zap.String("client", entry.Client),
+		zap.String("backend", entry.Backend),
+		zap.String("arguments", entry.Arguments),
+		zap.Int64("duration_ms", entry.DurationMs),
+		zap.String("outcome", string(entry.Outcome)),
+	}
+	if entry.UserID != nil {
+		fields = append(fields, zap.Uint("user_id", *entry.UserID))
+	}
+
+	if entry.Outcome == models.AuditOutcomeError {
+		fields = append(fields, zap.String("error_code", entry.ErrorCode), zap.String("error", entry.Error))
+		logger.Warn("MCP tool call failed", fields...)
+	} else {
+		logger.Info("MCP tool call", fields...)
+	}
+
+	if store != nil {
+		if err := store.Create(entry); err != nil {
+			logger.Warn("Failed to persist audit log", zap.String("tool", entry.Tool), zap.Error(err))
+		}
+	}
+}
+
+// redactArguments คืนสำเนาของ argument ที่ปิดค่าที่เป็นความลับและย่อข้อความที่ยาวเกินไป
+func redactArguments(arguments map[string]any) map[string]any {
+	if arguments == nil {
+		return nil
+	}
+
+	redacted := make(map[string]any, len(arguments))
+	for name, value := range arguments {
+		if isSensitiveArgument(name) {
+			redacted[name] = "[REDACTED]"
+			continue
+		}
+		redacted[name] = redactValue(value)
+	}
+	return redacted
+}
+
+// redactValue ย่อค่าของ argument ที่ซ้อนอยู่ใน object หรือ array
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactArguments(v)
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			values[i] = redactValue(item)
+		}
+		return values
+	case string:
+		if len(v) > maxAuditStringLength {
+			return fmt.Sprintf("[%d bytes omitted]", len(v))
+		}
+	}
+	return value
+}
+
+// isSensitiveArgument ตรวจว่าชื่อ argument บ่งบอกว่าเป็นข้อมูลลับ
+func isSensitiveArgument(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveArgumentNames {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultBackendName คือชื่อ backend ที่ใช้เมื่อไม่มีการตั้งค่า backend ใดๆ
+	defaultBackendName = "local"
+
+	// defaultAPIBaseURL คือ base URL ของ API ที่ใช้เมื่อไม่ได้ตั้งค่า MCP_API_BASE_URL
+	defaultAPIBaseURL = "http://localhost:8080"
+)
+
+// Backend คือ API ปลายทางที่ MCP server สามารถเรียกใช้ได้
+type Backend struct {
+	Name    string `json:"-"`
+	BaseURL string `json:"base_url"`
+}
+
+// Backends คือชุดของ backend ที่ตั้งค่าไว้ตอนเริ่มต้น โมเดลเลือกได้เฉพาะชื่อที่อยู่ในชุดนี้
+type Backends struct {
+	defaultName string
+	backends    map[string]Backend
+	// allowedHosts คือ host ใน MCP_ALLOWED_HOSTS หรือว่างเมื่อไม่จำกัด host
+	allowedHosts map[string]bool
+}
+
+// backendsFile คือรูปแบบของไฟล์ตั้งค่า backend (MCP_BACKENDS_FILE)
+type backendsFile struct {
+	Default      string             `json:"default"`
+	AllowedHosts []string           `json:"allowed_hosts"`
+	Backends     map[string]Backend `json:"backends"`
+}
+
+// NewBackends สร้างชุด backend และตรวจสอบว่าทุก URL อยู่ใน allowlist ของ host
+// ถ้า allowedHosts ว่างจะไม่มีการจำกัด host
+func NewBackends(defaultName string, configured map[string]Backend, allowedHosts []string) (*Backends, error) {
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("at least one backend must be configured")
+	}
+
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		if host = strings.TrimSpace(strings.ToLower(host)); host != "" {
+			allowed[host] = true
+		}
+	}
+
+	result := &Backends{
+		defaultName:  defaultName,
+		backends:     make(map[string]Backend, len(configured)),
+		allowedHosts: allowed,
+	}
+
+	for name, backend := range configured {
+		u, err := url.Parse(backend.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("backend %q has an invalid base URL %q", name, backend.BaseURL)
+		}
+		if len(allowed) > 0 && !allowed[strings.ToLower(u.Hostname())] {
+			return nil, fmt.Errorf("backend %q host %q is not in the allowed hosts", name, u.Hostname())
+		}
+
+		backend.Name = name
+		backend.BaseURL = strings.TrimSuffix(backend.BaseURL, "/")
+		result.backends[name] = backend
+	}
+
+	if result.defaultName == "" {
+		if len(result.backends) != 1 {
+			return nil, fmt.Errorf("a default backend is required when more than one backend is configured")
+		}
+		result.defaultName = result.Names()[0]
+	}
+	if _, ok := result.backends[result.defaultName]; !ok {
+		return nil, fmt.Errorf("default backend %q is not configured", result.defaultName)
+	}
+
+	return result, nil
+}
+
+// LoadBackendsFromEnv โหลดชุด backend จาก environment variables
+//
+//   - MCP_BACKENDS_FILE: path ของไฟล์ JSON (ดู configs/mcpserver/backends.json)
+//   - MCP_BACKENDS: รายการ "name=url" คั่นด้วยจุลภาค เช่น "local=http://localhost:8080,staging=https://staging.example.com"
+//   - MCP_DEFAULT_BACKEND: ชื่อ backend เริ่มต้น
+//   - MCP_ALLOWED_HOSTS: host ที่อนุญาต คั่นด้วยจุลภาค
+//
+// ถ้าไม่มีการตั้งค่าใดเลยจะใช้ backend "local" ที่ MCP_API_BASE_URL หรือ http://localhost:8080
+func LoadBackendsFromEnv() (*Backends, error) {
+	cfg := backendsFile{Backends: map[string]Backend{}}
+
+	if path := os.Getenv("MCP_BACKENDS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backends file: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse backends file: %w", err)
+		}
+		if cfg.Backends == nil {
+			cfg.Backends = map[string]Backend{}
+		}
+	}
+
+	if list := os.Getenv("MCP_BACKENDS"); list != "" {
+		for _, entry := range strings.Split(list, ",") {
+			name, baseURL, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok || name == "" || baseURL == "" {
+				return nil, fmt.Errorf("invalid MCP_BACKENDS entry %q (expected name=url)", entry)
+			}
+			cfg.Backends[name] = Backend{BaseURL: baseURL}
+		}
+	}
+
+	if name := os.Getenv("MCP_DEFAULT_BACKEND"); name != "" {
+		cfg.Default = name
+	}
+	if hosts := os.Getenv("MCP_ALLOWED_HOSTS"); hosts != "" {
+		cfg.AllowedHosts = strings.Split(hosts, ",")
+	}
+
+	if len(cfg.Backends) == 0 {
+		baseURL := os.Getenv("MCP_API_BASE_URL")
+		if baseURL == "" {
+			baseURL = defaultAPIBaseURL
+		}
+		cfg.Backends[defaultBackendName] = Backend{BaseURL: baseURL}
+		if cfg.Default == "" {
+			cfg.Default = defaultBackendName
+		}
+	}
+
+	return NewBackends(cfg.Default, cfg.Backends, cfg.AllowedHosts)
+}
+
+// defaultBackends คืนชุด backend ที่มีเพียง "local" ซึ่งใช้เมื่อไม่ได้กำหนด gateway ให้ CreateServer
+func defaultBackends() *Backends {
+	return &Backends{
+		defaultName: defaultBackendName,
+		backends: map[string]Backend{
+			defaultBackendName: {Name: defaultBackendName, BaseURL: defaultAPIBaseURL},
+		},
+	}
+}
+
+// withBackendArgument เพิ่ม argument "backend" ที่เลือกได้เฉพาะชื่อ backend ที่ gateway ตั้งค่าไว้
+// ถ้า gateway ไม่มี backend ให้เลือกจะไม่เพิ่ม argument
+func withBackendArgument(names []string) mcp.ToolOption {
+	if len(names) == 0 {
+		return func(*mcp.Tool) {}
+	}
+
+	return mcp.WithString("backend",
+		mcp.Description("Name of the configured backend to use (defaults to the server's default backend)"),
+		mcp.Enum(names...),
+	)
+}
+
+// Default คืน backend เริ่มต้น
+func (b *Backends) Default() Backend {
+	return b.backends[b.defaultName]
+}
+
+// Get คืน backend ตามชื่อ ถ้าชื่อว่างจะคืน backend เริ่มต้น
+func (b *Backends) Get(name string) (Backend, error) {
+	if name == "" {
+		return b.Default(), nil
+	}
+
+	backend, ok := b.backends[name]
+	if !ok {
+		return Backend{}, validationError("unknown backend %q (available: %s)", name, strings.Join(b.Names(), ", "))
+	}
+	return backend, nil
+}
+
+// allowsHost ตรวจว่า host อยู่ใน MCP_ALLOWED_HOSTS ที่ตั้งค่าไว้อย่างชัดเจน
+// คืน false เมื่อไม่ได้ตั้งค่า allowlist
+func (b *Backends) allowsHost(host string) bool {
+	return b.allowedHosts[strings.ToLower(host)]
+}
+
+// Names คืนชื่อ backend ทั้งหมดเรียงตามตัวอักษร
+func (b *Backends) Names() []string {
+	names := make([]string, 0, len(b.backends))
+	for name := range b.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
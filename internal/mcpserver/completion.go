@@ -0,0 +1,120 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues คือจำนวนค่าสูงสุดที่ส่งกลับใน completion/complete ตามข้อกำหนดของ MCP
+const maxCompletionValues = 100
+
+// maxCompletionTitleRunes คือจำนวนตัวอักษรสูงสุดของชื่อบันทึกที่ต่อท้าย ID ในค่าของ completion
+const maxCompletionTitleRunes = 40
+
+// noteIDArgument คือชื่อ argument ของ resource template และ prompt ที่รับ ID ของบันทึก
+const noteIDArgument = "id"
+
+// noteCompletionProvider เติม ID ของบันทึกให้ argument id ของ note://{id} และของ prompt เช่น note_to_checklist
+// MCP รองรับ completion เฉพาะ argument ของ prompt และ resource template จึงไม่ครอบคลุม argument ของ tool
+// ชื่อ backend มีเฉพาะใน argument ของ tool (login, get_visitor_count) ซึ่งประกาศเป็น enum ใน schema แทน
+type noteCompletionProvider struct {
+	server *Server
+}
+
+// CompleteResourceArgument เติม id ของ resource template note://{id}
+func (p noteCompletionProvider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	if uri != noteURIScheme+"{id}" || argument.Name != noteIDArgument {
+		return emptyCompletion(), nil
+	}
+	return p.server.completeNoteIDs(ctx, argument.Value)
+}
+
+// CompletePromptArgument เติม argument id ของ prompt ที่ทำงานกับบันทึก
+func (p noteCompletionProvider) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	if argument.Name != noteIDArgument {
+		return emptyCompletion(), nil
+	}
+	return p.server.completeNoteIDs(ctx, argument.Value)
+}
+
+// completeNoteIDs คืนบันทึกของผู้ใช้ที่ชื่อขึ้นต้นด้วยข้อความที่พิมพ์ (ไม่สนตัวพิมพ์) ในรูป noteCompletionValue
+// เรียงจากที่แก้ไขล่าสุด ถ้าข้อความขึ้นต้นด้วยตัวเลข บันทึกที่มี ID นั้นจะอยู่ลำดับแรก ผู้ใช้ที่ยังไม่ล็อกอินจะได้รายการว่าง
+// ดึงจาก API เพียงหน้าเดียวไม่เกิน maxCompletionValues รายการต่อการพิมพ์หนึ่งครั้ง
+func (s *Server) completeNoteIDs(ctx context.Context, value string) (*mcp.Completion, error) {
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNotLoggedIn) {
+			return emptyCompletion(), nil
+		}
+		return nil, err
+	}
+
+	value = strings.TrimSpace(value)
+	var byID *Note
+	if id, err := parseNoteID(value); err == nil {
+		// บันทึกที่ไม่มีหรือเข้าถึงไม่ได้ก็แค่ไม่ถูกเติม
+		byID, _ = s.gateway.GetNote(ctx, creds, uint(id))
+	}
+
+	notes, total, err := s.gateway.FindNotesByTitlePrefix(ctx, creds, value, maxCompletionValues)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+	if byID != nil {
+		values = append(values, noteCompletionValue(*byID))
+		total++
+	}
+	for _, note := range notes {
+		if byID != nil && note.ID == byID.ID {
+			total--
+			continue
+		}
+		if len(values) < maxCompletionValues {
+			values = append(values, noteCompletionValue(note))
+		}
+	}
+
+	return &mcp.Completion{Values: values, Total: int(total), HasMore: int(total) > len(values)}, nil
+}
+
+// noteCompletionValue คืนค่า completion ของบันทึกในรูป "{id}-{ชื่อบันทึก}" เช่น "12-shopping-list"
+// completion ของ MCP คืนได้เฉพาะข้อความที่ client ใช้เป็นค่าของ argument โดยตรง จึงต่อชื่อไว้หลัง ID ให้ผู้ใช้เห็นว่าเป็นบันทึกใด
+// โดย parseNoteID อ่านเฉพาะ ID ด้านหน้า
+func noteCompletionValue(note Note) string {
+	var slug strings.Builder
+	runes := 0
+	separate := false
+	for _, r := range strings.ToLower(note.Title) {
+		if runes == maxCompletionTitleRunes {
+			break
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			separate = true
+			continue
+		}
+		if separate && slug.Len() > 0 {
+			slug.WriteByte('-')
+		}
+		separate = false
+		slug.WriteRune(r)
+		runes++
+	}
+
+	if slug.Len() == 0 {
+		return strconv.Itoa(note.ID)
+	}
+	return fmt.Sprintf("%d-%s", note.ID, slug.String())
+}
+
+// emptyCompletion คืน completion ที่ไม่มีค่า
+func emptyCompletion() *mcp.Completion {
+	return &mcp.Completion{Values: []string{}}
+}
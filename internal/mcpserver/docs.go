@@ -0,0 +1,485 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// docURIScheme คือ scheme ของ resource เอกสาร เช่น doc://en
+	docURIScheme = "doc://"
+
+	// defaultDocLanguage คือภาษาเริ่มต้นของเอกสาร
+	defaultDocLanguage = "th"
+)
+
+// docLanguages คือภาษาที่รองรับ เรียงตามลำดับที่แสดงใน resources/list
+var docLanguages = []string{"th", "en"}
+
+// docText คือข้อความประกอบเอกสารของแต่ละภาษา ส่วนรายละเอียดของ tool, resource และ prompt มาจาก server
+type docText struct {
+	title          string
+	intro          string
+	tools          string
+	adminTools     string
+	adminToolsNote string
+	resources      string
+	templates      string
+	prompts        string
+	noArguments    string
+	required       string
+	optional       string
+	readOnly       string
+	additive       string
+	merge          string
+	destructive    string
+	values         string
+	between        string
+	atLeast        string
+	atMost         string
+	notes          string
+	// หมายเหตุแต่ละข้อแสดงเฉพาะเมื่อตรงกับการตั้งค่าของ server (ดู documentationNotes)
+	noteLogin        string
+	noteExternalAuth string
+	noteSubscribe    string
+	noteCompletion   string
+	notePolicy       string
+}
+
+var docTexts = map[string]docText{
+	"th": {
+		title:            "เอกสารการใช้งาน",
+		intro:            "เอกสารนี้สร้างจาก tool, resource และ prompt ที่ลงทะเบียนอยู่ใน server ขณะนี้",
+		tools:            "เครื่องมือ (tools)",
+		adminTools:       "เครื่องมือสำหรับ admin",
+		adminToolsNote:   "แสดงใน tools/list เฉพาะ session ที่มีบทบาท Admin หรือ Super Admin",
+		resources:        "Resources",
+		templates:        "Resource templates",
+		prompts:          "Prompts",
+		noArguments:      "ไม่มีพารามิเตอร์",
+		required:         "บังคับ",
+		optional:         "ไม่บังคับ",
+		readOnly:         "อ่านอย่างเดียว",
+		additive:         "เพิ่มข้อมูล",
+		merge:            "แก้ไขเฉพาะ field ที่ระบุ",
+		destructive:      "แก้ไขหรือลบข้อมูล ควรยืนยันก่อนเรียก",
+		values:           "ค่าที่ใช้ได้",
+		between:          "ระหว่าง %v ถึง %v",
+		atLeast:          "อย่างน้อย %v",
+		atMost:           "ไม่เกิน %v",
+		notes:            "หมายเหตุ",
+		noteLogin:        "login จะเก็บ token ไว้ใน session และใช้กับ tool อื่นโดยอัตโนมัติ tool ที่เข้าถึงข้อมูลของผู้ใช้ต้องล็อกอินก่อน",
+		noteExternalAuth: "tool ทำงานในนามผู้ใช้จาก JWT ใน header Authorization ของ HTTP request จึงไม่มี tool login และ logout",
+		noteSubscribe:    "resource note://{id} รองรับ resources/subscribe เพื่อรับ notification เมื่อบันทึกถูกแก้ไขหรือลบ",
+		noteCompletion:   "argument id ของ note://{id} และของ prompt รองรับ completion/complete โดยค้นจากชื่อบันทึกที่ขึ้นต้นด้วยข้อความที่พิมพ์ และคืนค่าในรูป {id}-{ชื่อบันทึก} ซึ่ง argument id ของ tool ก็รับได้ ชื่อ backend ไม่มี completion ให้ดูค่าที่เลือกได้จาก enum ใน schema ของ tool",
+		notePolicy:       "ผู้ดูแลระบบอาจปิดบาง tool ไว้ด้วย MCP_ALLOWED_TOOLS, MCP_DISABLED_TOOLS หรือ MCP_READ_ONLY tool เหล่านั้นจะไม่ปรากฏในเอกสารนี้",
+	},
+	"en": {
+		title:            "Documentation",
+		intro:            "This document is generated from the tools, resources and prompts currently registered on the server.",
+		tools:            "Tools",
+		adminTools:       "Admin tools",
+		adminToolsNote:   "Listed in tools/list only for sessions with the Admin or Super Admin role.",
+		resources:        "Resources",
+		templates:        "Resource templates",
+		prompts:          "Prompts",
+		noArguments:      "No arguments",
+		required:         "required",
+		optional:         "optional",
+		readOnly:         "read-only",
+		additive:         "adds data",
+		merge:            "updates only the given fields",
+		destructive:      "modifies or deletes data, confirm before calling",
+		values:           "allowed values",
+		between:          "between %v and %v",
+		atLeast:          "at least %v",
+		atMost:           "at most %v",
+		notes:            "Notes",
+		noteLogin:        "login stores the token in the session and other tools use it automatically. Tools that access user data require a login.",
+		noteExternalAuth: "Tools act as the user of the JWT in the Authorization header of the HTTP request, so there are no login and logout tools.",
+		noteSubscribe:    "The note://{id} resource supports resources/subscribe to get notified when the note is updated or deleted.",
+		noteCompletion:   "The id argument of note://{id} and of prompts supports completion/complete, matching note titles that start with the typed text and returning {id}-{title} values, which the id argument of tools also accepts. Backend names have no completion; pick them from the enum in the tool schema.",
+		notePolicy:       "Operators can disable tools with MCP_ALLOWED_TOOLS, MCP_DISABLED_TOOLS or MCP_READ_ONLY; disabled tools do not appear here.",
+	},
+}
+
+// สร้าง Tool สำหรับแสดงเอกสารการใช้งาน
+func CreateDocTool() mcp.Tool {
+	return mcp.NewTool("doc",
+		mcp.WithDescription("Show the documentation of this MCP server, generated from the registered tools, resources and prompts"),
+		readOnlyTool(),
+		mcp.WithString("lang",
+			mcp.Enum(docLanguages...),
+			mcp.Description(fmt.Sprintf("Language of the documentation (default %s)", defaultDocLanguage)),
+		),
+	)
+}
+
+// DocHandler เป็นฟังก์ชันสำหรับแสดงเอกสารการใช้งานของเซิร์ฟเวอร์ในภาษาที่เลือก
+func (s *Server) DocHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	lang := request.GetString("lang", defaultDocLanguage)
+	if _, ok := docTexts[lang]; !ok {
+		return nil, validationError("lang must be one of: %s", strings.Join(docLanguages, ", "))
+	}
+
+	documentation, err := s.generateDocumentation(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(documentation), nil
+}
+
+// CreateDocResource สร้าง resource doc://{lang} สำหรับเอกสารการใช้งานในภาษาที่ระบุ
+func CreateDocResource(lang string) mcp.Resource {
+	return mcp.NewResource(
+		docURIScheme+lang,
+		"doc-"+lang,
+		mcp.WithResourceDescription(fmt.Sprintf("Documentation of this MCP server in %q, generated from the registered tools, resources and prompts", lang)),
+		mcp.WithMIMEType("text/markdown"),
+	)
+}
+
+// DocResourceHandler อ่านเอกสารการใช้งานตามภาษาใน URI
+func (s *Server) DocResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	lang := strings.TrimPrefix(request.Params.URI, docURIScheme)
+	if _, ok := docTexts[lang]; !ok {
+		return nil, newToolError(ErrCodeNotFound, fmt.Sprintf("no documentation for language %q", lang))
+	}
+
+	documentation, err := s.generateDocumentation(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/markdown",
+			Text:     documentation,
+		},
+	}, nil
+}
+
+// docResource, docResourceTemplate และ docPrompt คือข้อมูลที่ใช้จากผลลัพธ์ของ resources/list,
+// resources/templates/list และ prompts/list
+type docResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+}
+
+type docResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+}
+
+type docPrompt struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Arguments   []mcp.PromptArgument `json:"arguments"`
+}
+
+// generateDocumentation สร้างเอกสาร markdown จาก server ที่กำลังจัดการ request อยู่
+// tool ผ่าน adminToolFilter ด้วย credentials ของ session เดียวกับ tools/list จึงแสดง admin tools เฉพาะกับ admin
+func (s *Server) generateDocumentation(ctx context.Context, lang string) (string, error) {
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return "", errors.New("documentation requires a running MCP server")
+	}
+	text := docTexts[lang]
+
+	var resources []docResource
+	if err := listFromServer(ctx, mcpServer, mcp.MethodResourcesList, "resources", &resources); err != nil {
+		return "", err
+	}
+	var templates []docResourceTemplate
+	if err := listFromServer(ctx, mcpServer, mcp.MethodResourcesTemplatesList, "resourceTemplates", &templates); err != nil {
+		return "", err
+	}
+	var prompts []docPrompt
+	if err := listFromServer(ctx, mcpServer, mcp.MethodPromptsList, "prompts", &prompts); err != nil {
+		return "", err
+	}
+
+	registered := make([]mcp.Tool, 0, len(mcpServer.ListTools()))
+	for _, tool := range mcpServer.ListTools() {
+		registered = append(registered, tool.Tool)
+	}
+
+	var tools, adminTools []mcp.Tool
+	for _, tool := range s.adminToolFilter(ctx, registered) {
+		if adminToolNames[tool.Name] {
+			adminTools = append(adminTools, tool)
+		} else {
+			tools = append(tools, tool)
+		}
+	}
+	sortTools(tools)
+	sortTools(adminTools)
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+	sort.Slice(templates, func(i, j int) bool { return templates[i].URITemplate < templates[j].URITemplate })
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "# %s %s %s\n\n%s\n", serverName, serverVersion, text.title, text.intro)
+
+	if len(tools) > 0 {
+		fmt.Fprintf(&doc, "\n## %s\n", text.tools)
+		for _, tool := range tools {
+			writeToolDoc(&doc, text, tool)
+		}
+	}
+
+	if len(adminTools) > 0 {
+		fmt.Fprintf(&doc, "\n## %s\n\n%s\n", text.adminTools, text.adminToolsNote)
+		for _, tool := range adminTools {
+			writeToolDoc(&doc, text, tool)
+		}
+	}
+
+	if len(resources) > 0 {
+		fmt.Fprintf(&doc, "\n## %s\n\n", text.resources)
+		for _, resource := range resources {
+			writeResourceDoc(&doc, resource.URI, resource.Description, resource.MIMEType)
+		}
+	}
+
+	if len(templates) > 0 {
+		fmt.Fprintf(&doc, "\n## %s\n\n", text.templates)
+		for _, template := range templates {
+			writeResourceDoc(&doc, template.URITemplate, template.Description, template.MIMEType)
+		}
+	}
+
+	if len(prompts) > 0 {
+		fmt.Fprintf(&doc, "\n## %s\n", text.prompts)
+		for _, prompt := range prompts {
+			fmt.Fprintf(&doc, "\n### %s\n\n%s\n\n", prompt.Name, prompt.Description)
+			if len(prompt.Arguments) == 0 {
+				fmt.Fprintf(&doc, "%s\n", text.noArguments)
+			}
+			for _, arg := range prompt.Arguments {
+				fmt.Fprintf(&doc, "- `%s` (%s): %s\n", arg.Name, requiredLabel(text, arg.Required), arg.Description)
+			}
+		}
+	}
+
+	fmt.Fprintf(&doc, "\n## %s\n\n", text.notes)
+	for _, line := range s.documentationNotes(mcpServer, text) {
+		fmt.Fprintf(&doc, "- %s\n", line)
+	}
+
+	return doc.String(), nil
+}
+
+// documentationNotes เลือกหมายเหตุที่เป็นจริงกับการตั้งค่าของ server
+// หมายเหตุของ login แสดงเมื่อ tool login ลงทะเบียนอยู่ และของ subscription แสดงเมื่อ transport ที่ใช้รองรับ resources/subscribe
+func (s *Server) documentationNotes(mcpServer *server.MCPServer, text docText) []string {
+	var notes []string
+	switch {
+	case s.externalAuth:
+		notes = append(notes, text.noteExternalAuth)
+	case mcpServer.GetTool("login") != nil:
+		notes = append(notes, text.noteLogin)
+	}
+
+	if s.subscribable.Load() {
+		notes = append(notes, text.noteSubscribe)
+	}
+	return append(notes, text.noteCompletion, text.notePolicy)
+}
+
+// listFromServer เรียก list method ของ server ผ่าน HandleMessage จนครบทุกหน้า แล้วถอดรายการใน field ที่ระบุลงใน out
+func listFromServer(ctx context.Context, s *server.MCPServer, method mcp.MCPMethod, field string, out interface{}) error {
+	var items []json.RawMessage
+	cursor := ""
+	for {
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		request, err := json.Marshal(map[string]interface{}{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      "doc",
+			"method":  method,
+			"params":  params,
+		})
+		if err != nil {
+			return err
+		}
+
+		response, ok := s.HandleMessage(ctx, request).(mcp.JSONRPCResponse)
+		if !ok {
+			return fmt.Errorf("failed to list %s", field)
+		}
+
+		resultJSON, err := json.Marshal(response.Result)
+		if err != nil {
+			return err
+		}
+		var page map[string]json.RawMessage
+		if err := json.Unmarshal(resultJSON, &page); err != nil {
+			return err
+		}
+
+		var pageItems []json.RawMessage
+		if raw, ok := page[field]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return err
+			}
+		}
+		items = append(items, pageItems...)
+
+		cursor = ""
+		if raw, ok := page["nextCursor"]; ok {
+			_ = json.Unmarshal(raw, &cursor)
+		}
+		if cursor == "" {
+			break
+		}
+	}
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(itemsJSON, out)
+}
+
+// writeToolDoc เขียนรายละเอียดของ tool หนึ่งรายการ
+func writeToolDoc(doc *strings.Builder, text docText, tool mcp.Tool) {
+	fmt.Fprintf(doc, "\n### %s\n\n%s\n\n", tool.Name, tool.Description)
+	if hint := toolHintLabel(text, tool); hint != "" {
+		fmt.Fprintf(doc, "_%s_\n\n", hint)
+	}
+
+	if len(tool.InputSchema.Properties) == 0 {
+		fmt.Fprintf(doc, "%s\n", text.noArguments)
+		return
+	}
+
+	names := make([]string, 0, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, _ := tool.InputSchema.Properties[name].(map[string]interface{})
+		required := containsName(tool.InputSchema.Required, name)
+		fmt.Fprintf(doc, "- `%s` (%s, %s)", name, schemaType(property), requiredLabel(text, required))
+		if description, _ := property["description"].(string); description != "" {
+			fmt.Fprintf(doc, ": %s", description)
+		}
+		if constraints := schemaConstraints(text, property); constraints != "" {
+			fmt.Fprintf(doc, " [%s]", constraints)
+		}
+		doc.WriteString("\n")
+	}
+}
+
+// writeResourceDoc เขียนรายละเอียดของ resource หรือ resource template หนึ่งรายการ
+func writeResourceDoc(doc *strings.Builder, uri, description, mimeType string) {
+	fmt.Fprintf(doc, "- `%s`", uri)
+	if mimeType != "" {
+		fmt.Fprintf(doc, " (%s)", mimeType)
+	}
+	if description != "" {
+		fmt.Fprintf(doc, ": %s", description)
+	}
+	doc.WriteString("\n")
+}
+
+// toolHintLabel แปลง annotation ของ tool เป็นข้อความ
+func toolHintLabel(text docText, tool mcp.Tool) string {
+	annotations := tool.Annotations
+	switch {
+	case annotations.ReadOnlyHint != nil && *annotations.ReadOnlyHint:
+		return text.readOnly
+	case annotations.DestructiveHint != nil && *annotations.DestructiveHint:
+		return text.destructive
+	case annotations.IdempotentHint != nil && *annotations.IdempotentHint:
+		return text.merge
+	case annotations.DestructiveHint != nil:
+		return text.additive
+	}
+	return ""
+}
+
+// schemaType คืนชนิดของ argument เช่น string หรือ array of string
+func schemaType(property map[string]interface{}) string {
+	typ, _ := property["type"].(string)
+	if typ == "" {
+		typ = "any"
+	}
+	if items, ok := property["items"].(map[string]interface{}); ok && typ == "array" {
+		if itemType, _ := items["type"].(string); itemType != "" {
+			return "array of " + itemType
+		}
+	}
+	return typ
+}
+
+// schemaConstraints คืนข้อจำกัดของ argument เช่นค่าที่ใช้ได้และช่วงของตัวเลข
+func schemaConstraints(text docText, property map[string]interface{}) string {
+	var parts []string
+
+	enum := property["enum"]
+	if items, ok := property["items"].(map[string]interface{}); ok && enum == nil {
+		enum = items["enum"]
+	}
+	if values := enumValues(enum); len(values) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", text.values, strings.Join(values, ", ")))
+	}
+
+	min, hasMin := property["minimum"]
+	max, hasMax := property["maximum"]
+	switch {
+	case hasMin && hasMax:
+		parts = append(parts, fmt.Sprintf(text.between, min, max))
+	case hasMin:
+		parts = append(parts, fmt.Sprintf(text.atLeast, min))
+	case hasMax:
+		parts = append(parts, fmt.Sprintf(text.atMost, max))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// enumValues แปลงค่า enum ของ schema เป็นรายการข้อความ
+func enumValues(enum interface{}) []string {
+	var values []string
+	switch v := enum.(type) {
+	case []string:
+		values = append(values, v...)
+	case []interface{}:
+		for _, value := range v {
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+	return values
+}
+
+// requiredLabel คืนข้อความว่า argument บังคับหรือไม่
+func requiredLabel(text docText, required bool) string {
+	if required {
+		return text.required
+	}
+	return text.optional
+}
+
+// sortTools เรียง tool ตามชื่อ
+func sortTools(tools []mcp.Tool) {
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+}
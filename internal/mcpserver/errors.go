@@ -0,0 +1,122 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Napat/mcpserver-demo/pkg/apiclient"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// รหัส error ที่ส่งกลับไปในผลลัพธ์ของ tool เพื่อให้โมเดลตัดสินใจได้ว่าจะทำอะไรต่อ
+const (
+	ErrCodeUnauthorized        = "unauthorized"
+	ErrCodeNotFound            = "not_found"
+	ErrCodeValidation          = "validation"
+	ErrCodeUpstreamUnavailable = "upstream_unavailable"
+	ErrCodeRateLimited         = "rate_limited"
+	ErrCodeInternal            = "internal"
+)
+
+// ToolError คือ error ของ tool ที่มีรหัสให้เครื่องอ่านได้
+type ToolError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// RetryAfter คือจำนวนวินาทีที่ควรรอก่อนเรียกใหม่ (เฉพาะ rate_limited)
+	RetryAfter int `json:"retry_after_seconds,omitempty"`
+}
+
+// Error คืนข้อความของ error
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// newToolError สร้าง ToolError ด้วยรหัสและข้อความที่กำหนด
+func newToolError(code, format string, args ...interface{}) *ToolError {
+	return &ToolError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// validationError สร้าง ToolError สำหรับ argument ที่ไม่ถูกต้อง
+func validationError(format string, args ...interface{}) *ToolError {
+	return newToolError(ErrCodeValidation, format, args...)
+}
+
+// toToolError แปลง error จาก handler หรือ gateway เป็น ToolError
+func toToolError(err error) *ToolError {
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return toolErr
+	}
+
+	if errors.Is(err, ErrNotLoggedIn) {
+		return newToolError(ErrCodeUnauthorized, "%s", err.Error())
+	}
+
+	var apiErr *apiclient.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden:
+			return newToolError(ErrCodeUnauthorized, "%s", apiErr.Message)
+		case apiErr.StatusCode == http.StatusNotFound:
+			return newToolError(ErrCodeNotFound, "%s", apiErr.Message)
+		case apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError:
+			return newToolError(ErrCodeUpstreamUnavailable, "%s", apiErr.Error())
+		case apiErr.StatusCode >= http.StatusBadRequest:
+			return newToolError(ErrCodeValidation, "%s", apiErr.Message)
+		}
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded) {
+		return newToolError(ErrCodeUpstreamUnavailable, "%s", err.Error())
+	}
+
+	return newToolError(ErrCodeInternal, "%s", err.Error())
+}
+
+// toolErrorResult สร้างผลลัพธ์ของ tool ที่เป็น error พร้อม payload {"error": {...}}
+func toolErrorResult(err error) *mcp.CallToolResult {
+	toolErr := toToolError(err)
+	payload := map[string]interface{}{"error": toolErr}
+
+	result := mcp.NewToolResultError(fmt.Sprintf("%s: %s", toolErr.Code, toolErr.Message))
+	if data, err := json.Marshal(payload); err == nil {
+		result.Content = append(result.Content, mcp.NewTextContent(string(data)))
+	}
+	result.StructuredContent = payload
+	return result
+}
+
+// toolErrorMiddleware แปลง Go error ที่ handler คืนมาเป็นผลลัพธ์ของ tool ที่โมเดลนำไปใช้ต่อได้
+// แทนที่จะเป็น protocol error
+func toolErrorMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil {
+			return toolErrorResult(err), nil
+		}
+		return result, nil
+	}
+}
+
+// toolResult สร้างผลลัพธ์ของ tool ที่มีทั้งข้อความสำหรับคนอ่านและ JSON ของ data
+// data ควรเป็น object (struct หรือ map) เพื่อใช้เป็น structured content ได้
+func toolResult(text string, data interface{}) (*mcp.CallToolResult, error) {
+	dataJSON, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(text),
+			mcp.NewTextContent(string(dataJSON)),
+		},
+		StructuredContent: data,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package mcpserver
+
+import (
+	"context"
+	"time"
+)
+
+// IGateway คือช่องทางที่ tool handlers ใช้เข้าถึงข้อมูลของระบบ
+// มีสองแบบคือ HTTPGateway (เรียก REST API) และ ServiceGateway (เรียก service layer โดยตรงในกระบวนการเดียวกัน)
+type IGateway interface {
+	// Backends คืนชื่อ backend ที่เลือกได้ หรือค่าว่างถ้า gateway ไม่มี backend ให้เลือก
+	Backends() []string
+	Login(ctx context.Context, backend, email, password string) (*Credentials, error)
+	GetVisitorCount(ctx context.Context, backend string) (int64, error)
+	ListNotes(ctx context.Context, creds *Credentials) ([]Note, error)
+	// ListNotesUpdatedSince คืนบันทึกทั้งหมดที่แก้ไขตั้งแต่ since เรียงจากที่แก้ไขล่าสุด
+	ListNotesUpdatedSince(ctx context.Context, creds *Credentials, since time.Time) ([]Note, error)
+	// FindNotesByTitlePrefix คืนบันทึกที่ชื่อขึ้นต้นด้วย prefix (ไม่สนตัวพิมพ์) ไม่เกิน limit รายการ เรียงจากที่แก้ไขล่าสุด
+	// พร้อมจำนวนบันทึกที่ตรงทั้งหมด
+	FindNotesByTitlePrefix(ctx context.Context, creds *Credentials, prefix string, limit int) ([]Note, int64, error)
+	GetNote(ctx context.Context, creds *Credentials, id uint) (*Note, error)
+	CreateNote(ctx context.Context, creds *Credentials, input NoteInput) (*Note, error)
+	UpdateNote(ctx context.Context, creds *Credentials, id uint, input NoteInput) (*Note, error)
+	DeleteNote(ctx context.Context, creds *Credentials, id uint) error
+	GetProfile(ctx context.Context, creds *Credentials) (*Profile, error)
+	UpdateProfile(ctx context.Context, creds *Credentials, input ProfileInput) (*Profile, error)
+	GetLoginHistory(ctx context.Context, creds *Credentials, limit int) ([]LoginRecord, error)
+	// UploadProfileImage อัปโหลดรูปโปรไฟล์และคืน URL ของรูปใหม่
+	UploadProfileImage(ctx context.Context, creds *Credentials, filename, contentType string, data []byte) (string, error)
+
+	// method สำหรับ admin ผู้เรียกต้องมีบทบาท Admin หรือ Super Admin
+	ListUsers(ctx context.Context, creds *Credentials) ([]UserAccount, error)
+	DeactivateUser(ctx context.Context, creds *Credentials, id uint) (*UserAccount, error)
+	ChangeUserRole(ctx context.Context, creds *Credentials, id uint, role uint8) (*UserAccount, error)
+	GetUserLoginHistory(ctx context.Context, creds *Credentials, id uint, limit int) ([]LoginRecord, error)
+}
+
+// Credentials คือข้อมูลการยืนยันตัวตนของผู้ใช้ที่ล็อกอินแล้ว
+type Credentials struct {
+	// Backend คือ backend ที่ใช้ล็อกอิน
+	Backend Backend
+	// Token คือ JWT token สำหรับเรียก API (อาจว่างในโหมด in-process)
+	Token string
+	// User คือข้อมูลผู้ใช้ที่ล็อกอิน
+	User SessionUser
+}
+
+// Note คือโครงสร้างสำหรับข้อมูลบันทึก
+type Note struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Profile คือโปรไฟล์ของผู้ใช้ที่ล็อกอิน
+type Profile struct {
+	ID              uint64     `json:"id"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Gender          string     `json:"gender"`
+	Role            uint8      `json:"role"`
+	Roles           []string   `json:"roles"`
+	ProfileImageURL string     `json:"profile_image_url,omitempty"`
+	LastLoginTime   *time.Time `json:"last_login_time,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+}
+
+// UserAccount คือบัญชีผู้ใช้ที่ admin เห็น
+type UserAccount struct {
+	ID            uint64     `json:"id"`
+	Email         string     `json:"email"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	Role          uint8      `json:"role"`
+	Roles         []string   `json:"roles"`
+	Active        bool       `json:"active"`
+	LastLoginTime *time.Time `json:"last_login_time,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+}
+
+// ProfileInput คือข้อมูลที่ใช้แก้ไขโปรไฟล์ (ตรงกับ ProfileUpdateRequest)
+type ProfileInput struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Gender    string `json:"gender"`
+}
+
+// LoginRecord คือประวัติการเข้าสู่ระบบหนึ่งครั้ง
+type LoginRecord struct {
+	LoginTime time.Time `json:"login_time"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+}
+
+// NoteInput คือข้อมูลที่ใช้สร้างหรือแก้ไขบันทึก (ตรงกับ CreateNoteRequest/UpdateNoteRequest)
+type NoteInput struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
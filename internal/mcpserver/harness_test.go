@@ -0,0 +1,496 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// update เขียนไฟล์ golden ใหม่จากผลลัพธ์ปัจจุบัน: go test ./internal/mcpserver -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// apiFixtures คือข้อมูลตั้งต้นของ fake API จาก testdata/fixtures/api.json
+type apiFixtures struct {
+	Now          time.Time             `json:"now"`
+	Visitors     int64                 `json:"visitors"`
+	Users        []fixtureUser         `json:"users"`
+	Notes        []models.Note         `json:"notes"`
+	LoginHistory []models.LoginHistory `json:"login_history"`
+}
+
+// fixtureUser คือผู้ใช้ใน fixture พร้อมรหัสผ่านที่ใช้ล็อกอิน
+type fixtureUser struct {
+	models.User
+	Password string `json:"password"`
+}
+
+// fakeAPI จำลอง /api routes ของ cmd/api ด้วย httptest และบันทึก request ที่ได้รับ
+type fakeAPI struct {
+	mu       sync.Mutex
+	data     apiFixtures
+	nextID   uint
+	requests []string
+	server   *httptest.Server
+}
+
+// newFakeAPI เริ่ม fake API จาก fixture และปิดเมื่อ test จบ
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "fixtures", "api.json"))
+	if err != nil {
+		t.Fatalf("failed to read API fixtures: %v", err)
+	}
+
+	api := &fakeAPI{}
+	if err := json.Unmarshal(raw, &api.data); err != nil {
+		t.Fatalf("failed to parse API fixtures: %v", err)
+	}
+	for _, note := range api.data.Notes {
+		if note.ID > api.nextID {
+			api.nextID = note.ID
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/auth/login", api.login)
+	mux.HandleFunc("GET /api/visitors", api.visitors)
+	mux.HandleFunc("GET /api/me", api.authed(api.getProfile))
+	mux.HandleFunc("PUT /api/me", api.authed(api.updateProfile))
+	mux.HandleFunc("GET /api/me/login-history", api.authed(api.loginHistory))
+	mux.HandleFunc("GET /api/notes", api.authed(api.listNotes))
+	mux.HandleFunc("POST /api/notes", api.authed(api.createNote))
+	mux.HandleFunc("GET /api/notes/{id}", api.authed(api.getNote))
+	mux.HandleFunc("PUT /api/notes/{id}", api.authed(api.updateNote))
+	mux.HandleFunc("DELETE /api/notes/{id}", api.authed(api.deleteNote))
+	mux.HandleFunc("GET /api/admin/users", api.admin(api.listUsers))
+	mux.HandleFunc("POST /api/admin/users/{id}/deactivate", api.admin(api.deactivateUser))
+	mux.HandleFunc("PUT /api/admin/users/{id}/role", api.admin(api.updateRole))
+	mux.HandleFunc("GET /api/admin/users/{id}/login-history", api.admin(api.userLoginHistory))
+
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		api.requests = append(api.requests, r.Method+" "+r.URL.RequestURI())
+		api.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(api.server.Close)
+
+	return api
+}
+
+// takeRequests คืน request ที่ได้รับตั้งแต่ครั้งก่อนและล้างรายการ
+func (a *fakeAPI) takeRequests() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	requests := a.requests
+	a.requests = nil
+	if requests == nil {
+		requests = []string{}
+	}
+	return requests
+}
+
+// authed ตรวจ token รูปแบบ token-{user id} แล้วส่งผู้ใช้ให้ handler
+func (a *fakeAPI) authed(next func(http.ResponseWriter, *http.Request, *fixtureUser)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		id, err := strconv.ParseUint(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer token-"), 10, 64)
+		user := a.user(id)
+		if err != nil || user == nil || !user.Active {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid or expired token"})
+			return
+		}
+		next(w, r, user)
+	}
+}
+
+// admin ตรวจว่าผู้ใช้เป็น Admin หรือ Super Admin แบบเดียวกับ middleware.AdminMiddleware
+func (a *fakeAPI) admin(next func(http.ResponseWriter, *http.Request, *fixtureUser)) http.HandlerFunc {
+	return a.authed(func(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+		if !user.IsAdmin() {
+			writeJSON(w, http.StatusForbidden, map[string]string{"message": "Admin access required"})
+			return
+		}
+		next(w, r, user)
+	})
+}
+
+// user หาผู้ใช้ตาม ID
+func (a *fakeAPI) user(id uint64) *fixtureUser {
+	for i := range a.data.Users {
+		if a.data.Users[i].ID == id {
+			return &a.data.Users[i]
+		}
+	}
+	return nil
+}
+
+func (a *fakeAPI) login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, user := range a.data.Users {
+		if user.Email == req.Email && user.Password == req.Password && user.Active {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"token": fmt.Sprintf("token-%d", user.ID), "user": user.User})
+			return
+		}
+	}
+	writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid email or password"})
+}
+
+func (a *fakeAPI) visitors(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]int64{"visitor_count": a.data.Visitors})
+}
+
+func (a *fakeAPI) getProfile(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	writeJSON(w, http.StatusOK, user.User)
+}
+
+func (a *fakeAPI) updateProfile(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	var req struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Gender    string `json:"gender"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	if req.FirstName == "" || req.LastName == "" || (req.Gender != "male" && req.Gender != "female" && req.Gender != "other") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid profile"})
+		return
+	}
+
+	user.FirstName, user.LastName, user.Gender = req.FirstName, req.LastName, req.Gender
+	writeJSON(w, http.StatusOK, user.User)
+}
+
+func (a *fakeAPI) loginHistory(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	writeJSON(w, http.StatusOK, a.history(user.ID, r.URL.Query().Get("limit")))
+}
+
+func (a *fakeAPI) userLoginHistory(w http.ResponseWriter, r *http.Request, _ *fixtureUser) {
+	id, _ := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	writeJSON(w, http.StatusOK, a.history(id, r.URL.Query().Get("limit")))
+}
+
+// history คืนประวัติการล็อกอินของผู้ใช้ ใหม่สุดก่อน
+func (a *fakeAPI) history(userID uint64, limitParam string) []models.LoginHistory {
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	history := []models.LoginHistory{}
+	for _, record := range a.data.LoginHistory {
+		if record.UserID == userID {
+			history = append(history, record)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].LoginTime.After(history[j].LoginTime) })
+	if len(history) > limit {
+		history = history[:limit]
+	}
+	return history
+}
+
+// listNotes ตอบเป็น models.NotePage ใหม่สุดก่อนตาม created_at หรือ updated_at (sort=updated_at)
+// กรองตาม title และ title_prefix แบบไม่สนตัวพิมพ์และตาม updated_after โดย cursor ของ fake คือ offset ของหน้าถัดไป
+func (a *fakeAPI) listNotes(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	query := r.URL.Query()
+	var updatedAfter time.Time
+	if value := query.Get("updated_after"); value != "" {
+		var err error
+		if updatedAfter, err = time.Parse(time.RFC3339, value); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid updated_after"})
+			return
+		}
+	}
+
+	notes := []models.Note{}
+	title := strings.ToLower(query.Get("title"))
+	prefix := strings.ToLower(query.Get("title_prefix"))
+	for _, note := range a.data.Notes {
+		lowerTitle := strings.ToLower(note.Title)
+		if uint64(note.UserID) == user.ID && strings.Contains(lowerTitle, title) && strings.HasPrefix(lowerTitle, prefix) &&
+			!note.UpdatedAt.Before(updatedAfter) {
+			notes = append(notes, note)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if query.Get("sort") == "updated_at" {
+			return notes[i].UpdatedAt.After(notes[j].UpdatedAt)
+		}
+		return notes[i].CreatedAt.After(notes[j].CreatedAt)
+	})
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(query.Get("cursor"))
+	offset = min(offset, len(notes))
+	end := min(offset+limit, len(notes))
+
+	page := models.NotePage{Notes: notes[offset:end], Total: int64(len(notes)), Limit: limit}
+	if end < len(notes) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (a *fakeAPI) createNote(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	var req struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	if req.Title == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Title is required"})
+		return
+	}
+
+	a.nextID++
+	note := models.Note{
+		ID:        a.nextID,
+		Title:     req.Title,
+		Content:   req.Content,
+		UserID:    uint(user.ID),
+		CreatedAt: a.data.Now,
+		UpdatedAt: a.data.Now,
+	}
+	a.data.Notes = append(a.data.Notes, note)
+	writeJSON(w, http.StatusCreated, note)
+}
+
+func (a *fakeAPI) getNote(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	if note := a.ownNote(w, r, user); note != nil {
+		writeJSON(w, http.StatusOK, note)
+	}
+}
+
+func (a *fakeAPI) updateNote(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	note := a.ownNote(w, r, user)
+	if note == nil {
+		return
+	}
+
+	var req struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	note.Title, note.Content, note.UpdatedAt = req.Title, req.Content, a.data.Now
+	writeJSON(w, http.StatusOK, note)
+}
+
+func (a *fakeAPI) deleteNote(w http.ResponseWriter, r *http.Request, user *fixtureUser) {
+	note := a.ownNote(w, r, user)
+	if note == nil {
+		return
+	}
+
+	for i := range a.data.Notes {
+		if a.data.Notes[i].ID == note.ID {
+			a.data.Notes = append(a.data.Notes[:i], a.data.Notes[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownNote หาบันทึกตาม {id} ที่เป็นของผู้ใช้ และตอบ 404 เมื่อไม่พบ
+func (a *fakeAPI) ownNote(w http.ResponseWriter, r *http.Request, user *fixtureUser) *models.Note {
+	id, _ := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	for i := range a.data.Notes {
+		if uint64(a.data.Notes[i].ID) == id && uint64(a.data.Notes[i].UserID) == user.ID {
+			return &a.data.Notes[i]
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "Note not found"})
+	return nil
+}
+
+func (a *fakeAPI) listUsers(w http.ResponseWriter, r *http.Request, _ *fixtureUser) {
+	users := make([]models.User, 0, len(a.data.Users))
+	for _, user := range a.data.Users {
+		users = append(users, user.User)
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (a *fakeAPI) deactivateUser(w http.ResponseWriter, r *http.Request, admin *fixtureUser) {
+	id, _ := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	user := a.user(id)
+	switch {
+	case user == nil:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "User not found"})
+	case user.ID == admin.ID:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "cannot deactivate your own account"})
+	case user.Role.HasRole(models.RoleSuperAdmin) && !admin.Role.HasRole(models.RoleSuperAdmin):
+		writeJSON(w, http.StatusForbidden, map[string]string{"message": "only super admins can manage super admins"})
+	default:
+		user.Active = false
+		writeJSON(w, http.StatusOK, user.User)
+	}
+}
+
+func (a *fakeAPI) updateRole(w http.ResponseWriter, r *http.Request, _ *fixtureUser) {
+	id, _ := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	user := a.user(id)
+	if user == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "User not found"})
+		return
+	}
+
+	var req struct {
+		Role models.UserRole `json:"role"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	user.Role = req.Role
+	writeJSON(w, http.StatusOK, user.User)
+}
+
+// writeJSON เขียน response แบบ JSON
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// noRoots ทำให้ in-process transport สร้าง client session ซึ่ง tool อย่าง login ต้องใช้
+type noRoots struct{}
+
+func (noRoots) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return &mcp.ListRootsResult{Roots: []mcp.Root{}}, nil
+}
+
+// harness คือ MCP server จาก CreateServer ที่เชื่อมกับ MCP client แบบ in-process และ fake API
+type harness struct {
+	t      *testing.T
+	api    *fakeAPI
+	client *client.Client
+}
+
+// newHarness สร้าง server ที่เรียก fake API ผ่าน HTTPGateway พร้อม option เพิ่มเติม และ initialize client
+func newHarness(t *testing.T, opts ...Option) *harness {
+	t.Helper()
+
+	api := newFakeAPI(t)
+	backends, err := NewBackends("local", map[string]Backend{"local": {BaseURL: api.server.URL}}, nil)
+	if err != nil {
+		t.Fatalf("failed to create backends: %v", err)
+	}
+
+	s := CreateServer(append([]Option{WithBackends(backends)}, opts...)...)
+	c := client.NewClient(transport.NewInProcessTransportWithOptions(s.MCPServer, transport.WithRootsHandler(noRoots{})))
+	t.Cleanup(func() { c.Close() })
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "mcpserver-test", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("failed to initialize client: %v", err)
+	}
+
+	return &harness{t: t, api: api, client: c}
+}
+
+// login ล็อกอินด้วยผู้ใช้จาก fixture และล้าง request ที่บันทึกไว้
+func (h *harness) login(email, password string) {
+	h.t.Helper()
+
+	result := h.callTool("login", map[string]interface{}{"email": email, "password": password})
+	if result.IsError {
+		h.t.Fatalf("login as %s failed: %v", email, result.Content)
+	}
+	h.api.takeRequests()
+}
+
+// callTool เรียก tool และคืนผลลัพธ์ (error ของ tool อยู่ใน result.IsError)
+func (h *harness) callTool(name string, args map[string]interface{}) *mcp.CallToolResult {
+	h.t.Helper()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	result, err := h.client.CallTool(context.Background(), request)
+	if err != nil {
+		h.t.Fatalf("tools/call %s failed: %v", name, err)
+	}
+	return result
+}
+
+// listTools คืน tool ที่ session ปัจจุบันเห็น
+func (h *harness) listTools() []mcp.Tool {
+	h.t.Helper()
+
+	result, err := h.client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		h.t.Fatalf("tools/list failed: %v", err)
+	}
+	sortTools(result.Tools)
+	return result.Tools
+}
+
+// assertGolden เทียบ value ในรูป JSON กับ testdata/golden/{name}.golden
+func assertGolden(t *testing.T, name string, value interface{}) {
+	t.Helper()
+
+	var got bytes.Buffer
+	encoder := json.NewEncoder(&got)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		t.Fatalf("failed to marshal %s: %v", name, err)
+	}
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s (run go test ./internal/mcpserver -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("%s does not match the golden file; review the diff after running go test ./internal/mcpserver -update\n--- want\n%s\n--- got\n%s", path, want, got.Bytes())
+	}
+}
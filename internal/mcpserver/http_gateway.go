@@ -0,0 +1,301 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/Napat/mcpserver-demo/pkg/apiclient"
+)
+
+// HTTPGateway เข้าถึงข้อมูลผ่าน REST API ของ backend ที่ตั้งค่าไว้โดยใช้ apiclient
+type HTTPGateway struct {
+	backends *Backends
+	clients  map[string]*apiclient.Client
+}
+
+// NewHTTPGateway สร้าง instance ใหม่ของ HTTPGateway พร้อม apiclient ของแต่ละ backend
+func NewHTTPGateway(backends *Backends, opts ...apiclient.Option) *HTTPGateway {
+	clients := make(map[string]*apiclient.Client)
+	for _, name := range backends.Names() {
+		backend, _ := backends.Get(name)
+		clients[name] = apiclient.New(backend.BaseURL, opts...)
+	}
+
+	return &HTTPGateway{
+		backends: backends,
+		clients:  clients,
+	}
+}
+
+// Backends คืนชื่อ backend ทั้งหมดที่ตั้งค่าไว้
+func (g *HTTPGateway) Backends() []string {
+	return g.backends.Names()
+}
+
+// DefaultBackend คืน backend เริ่มต้น
+func (g *HTTPGateway) DefaultBackend() Backend {
+	return g.backends.Default()
+}
+
+// Login ล็อกอินผ่าน POST /api/auth/login
+func (g *HTTPGateway) Login(ctx context.Context, backendName, email, password string) (*Credentials, error) {
+	backend, err := g.backends.Get(backendName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.clients[backend.Name].Login(ctx, apiclient.LoginRequest{Email: email, Password: password})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Token == "" {
+		return nil, errors.New("no token received in response")
+	}
+
+	return &Credentials{
+		Backend: backend,
+		Token:   resp.Token,
+		User:    sessionUserFromModel(&resp.User),
+	}, nil
+}
+
+// GetVisitorCount ดึงจำนวนผู้เข้าชมผ่าน GET /api/visitors
+func (g *HTTPGateway) GetVisitorCount(ctx context.Context, backendName string) (int64, error) {
+	backend, err := g.backends.Get(backendName)
+	if err != nil {
+		return 0, err
+	}
+
+	return g.clients[backend.Name].GetVisitorCount(ctx)
+}
+
+// ListNotes ดึงรายการบันทึกผ่าน GET /api/notes
+func (g *HTTPGateway) ListNotes(ctx context.Context, creds *Credentials) ([]Note, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := client.ListNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, *noteFromModel(&notes[i]))
+	}
+	return result, nil
+}
+
+// ListNotesUpdatedSince ดึงบันทึกทุกหน้าผ่าน GET /api/notes?updated_after=...&sort=updated_at
+func (g *HTTPGateway) ListNotesUpdatedSince(ctx context.Context, creds *Credentials, since time.Time) ([]Note, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := client.ListAllNotes(ctx, apiclient.NoteListQuery{UpdatedAfter: &since, Sort: "updated_at", Order: "desc"})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, *noteFromModel(&notes[i]))
+	}
+	return result, nil
+}
+
+// FindNotesByTitlePrefix ดึงบันทึกหน้าเดียวผ่าน GET /api/notes?title_prefix=...&limit=...
+func (g *HTTPGateway) FindNotesByTitlePrefix(ctx context.Context, creds *Credentials, prefix string, limit int) ([]Note, int64, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, err := client.ListNotesPage(ctx, apiclient.NoteListQuery{Limit: limit, TitlePrefix: prefix, Sort: "updated_at", Order: "desc"})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]Note, 0, len(page.Notes))
+	for i := range page.Notes {
+		result = append(result, *noteFromModel(&page.Notes[i]))
+	}
+	return result, page.Total, nil
+}
+
+// GetNote ดึงบันทึกผ่าน GET /api/notes/{id}
+func (g *HTTPGateway) GetNote(ctx context.Context, creds *Credentials, id uint) (*Note, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := client.GetNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return noteFromModel(note), nil
+}
+
+// CreateNote สร้างบันทึกผ่าน POST /api/notes
+func (g *HTTPGateway) CreateNote(ctx context.Context, creds *Credentials, input NoteInput) (*Note, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := client.CreateNote(ctx, apiclient.NoteRequest(input))
+	if err != nil {
+		return nil, err
+	}
+	return noteFromModel(note), nil
+}
+
+// UpdateNote แก้ไขบันทึกผ่าน PUT /api/notes/{id}
+func (g *HTTPGateway) UpdateNote(ctx context.Context, creds *Credentials, id uint, input NoteInput) (*Note, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := client.UpdateNote(ctx, id, apiclient.NoteRequest(input))
+	if err != nil {
+		return nil, err
+	}
+	return noteFromModel(note), nil
+}
+
+// DeleteNote ลบบันทึกผ่าน DELETE /api/notes/{id}
+func (g *HTTPGateway) DeleteNote(ctx context.Context, creds *Credentials, id uint) error {
+	client, err := g.client(creds)
+	if err != nil {
+		return err
+	}
+	return client.DeleteNote(ctx, id)
+}
+
+// GetProfile ดึงโปรไฟล์ผ่าน GET /api/me
+func (g *HTTPGateway) GetProfile(ctx context.Context, creds *Credentials) (*Profile, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := client.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return profileFromModel(user), nil
+}
+
+// UpdateProfile แก้ไขโปรไฟล์ผ่าน PUT /api/me
+func (g *HTTPGateway) UpdateProfile(ctx context.Context, creds *Credentials, input ProfileInput) (*Profile, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := client.UpdateProfile(ctx, apiclient.ProfileUpdateRequest(input))
+	if err != nil {
+		return nil, err
+	}
+	return profileFromModel(user), nil
+}
+
+// GetLoginHistory ดึงประวัติการเข้าสู่ระบบผ่าน GET /api/me/login-history
+func (g *HTTPGateway) GetLoginHistory(ctx context.Context, creds *Credentials, limit int) ([]LoginRecord, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := client.GetLoginHistory(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return loginRecordsFromModel(history), nil
+}
+
+// UploadProfileImage อัปโหลดรูปโปรไฟล์ผ่าน POST /api/me/profile-image
+func (g *HTTPGateway) UploadProfileImage(ctx context.Context, creds *Credentials, filename, contentType string, data []byte) (string, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return "", err
+	}
+	return client.UploadProfileImage(ctx, filename, contentType, data)
+}
+
+// ListUsers ดึงรายชื่อผู้ใช้ผ่าน GET /api/admin/users
+func (g *HTTPGateway) ListUsers(ctx context.Context, creds *Credentials) ([]UserAccount, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := client.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return userAccountsFromModel(users), nil
+}
+
+// DeactivateUser ปิดการใช้งานผู้ใช้ผ่าน POST /api/admin/users/{id}/deactivate
+func (g *HTTPGateway) DeactivateUser(ctx context.Context, creds *Credentials, id uint) (*UserAccount, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := client.DeactivateUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return userAccountFromModel(user), nil
+}
+
+// ChangeUserRole เปลี่ยนบทบาทของผู้ใช้ผ่าน PUT /api/admin/users/{id}/role
+func (g *HTTPGateway) ChangeUserRole(ctx context.Context, creds *Credentials, id uint, role uint8) (*UserAccount, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := client.UpdateUserRole(ctx, id, apiclient.UpdateRoleRequest{Role: models.UserRole(role)})
+	if err != nil {
+		return nil, err
+	}
+	return userAccountFromModel(user), nil
+}
+
+// GetUserLoginHistory ดึงประวัติการเข้าสู่ระบบของผู้ใช้ผ่าน GET /api/admin/users/{id}/login-history
+func (g *HTTPGateway) GetUserLoginHistory(ctx context.Context, creds *Credentials, id uint, limit int) ([]LoginRecord, error) {
+	client, err := g.client(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := client.GetUserLoginHistory(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return loginRecordsFromModel(history), nil
+}
+
+// client คืน apiclient ของ backend ใน credentials พร้อม token ของผู้ใช้
+func (g *HTTPGateway) client(creds *Credentials) (*apiclient.Client, error) {
+	if creds == nil || creds.Token == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	backend, err := g.backends.Get(creds.Backend.Name)
+	if err != nil {
+		return nil, err
+	}
+	return g.clients[backend.Name].WithToken(creds.Token), nil
+}
@@ -0,0 +1,54 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Napat/mcpserver-demo/pkg/middleware"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewHTTPHandler สร้าง http.Handler ที่ให้บริการ MCP server ผ่าน streamable HTTP ในนามผู้ใช้จาก JWT
+// ใช้สำหรับ mount MCP server เข้ากับ route ของ cmd/api และต้องอยู่หลัง middleware.JWTMiddleware เสมอ
+// tool จึงทำงานในนามผู้ใช้ที่เรียกโดยไม่ต้องใช้ tool login
+func NewHTTPHandler(s *Server) http.Handler {
+	return credentialsHandler(s.subscriptionHandler(server.NewStreamableHTTPServer(s.MCPServer)))
+}
+
+// credentialsHandler แปลง JWT claims ที่ middleware.JWTMiddleware ตรวจสอบแล้วเป็น Credentials
+// และแนบไปกับ context ของ request
+func credentialsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.ClaimsFromContext(r.Context())
+		if !ok {
+			writeJSONError(w, http.StatusUnauthorized, "User not authenticated")
+			return
+		}
+
+		userID, ok := claims["user_id"].(float64)
+		if !ok || userID <= 0 {
+			writeJSONError(w, http.StatusUnauthorized, "Invalid user ID")
+			return
+		}
+		role, _ := claims["role"].(float64)
+
+		creds := &Credentials{
+			Backend: Backend{Name: inProcessBackendName},
+			Token:   strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+			User: SessionUser{
+				ID:   uint64(userID),
+				Role: uint8(role),
+			},
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithCredentials(r.Context(), creds)))
+	})
+}
+
+// writeJSONError ตอบ error ในรูปแบบเดียวกับ middleware ของ Echo
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,191 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// สร้าง Tool สำหรับดึงรายการบันทึกทั้งหมดของผู้ใช้
+func CreateListNotesTool() mcp.Tool {
+	return mcp.NewTool("list_notes",
+		mcp.WithDescription("List all notes of the logged-in user, newest first"),
+		readOnlyTool(),
+	)
+}
+
+// ListNotesHandler เป็นฟังก์ชันสำหรับดึงรายการบันทึกทั้งหมด
+func (s *Server) ListNotesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.gateway.ListNotes(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Found %d notes", len(notes))
+	for _, note := range notes {
+		fmt.Fprintf(&text, "\n- [%d] %s", note.ID, note.Title)
+	}
+
+	return toolResult(text.String(), map[string]interface{}{"notes": notes})
+}
+
+// สร้าง Tool สำหรับสร้างบันทึกใหม่
+func CreateCreateNoteTool() mcp.Tool {
+	return mcp.NewTool("create_note",
+		mcp.WithDescription("Create a new note for the logged-in user"),
+		additiveTool(),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Title of the note"),
+		),
+		mcp.WithString("content",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Content of the note"),
+		),
+	)
+}
+
+// CreateNoteHandler เป็นฟังก์ชันสำหรับสร้างบันทึกใหม่
+func (s *Server) CreateNoteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := noteInputFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.gateway.CreateNote(ctx, creds, *input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult(fmt.Sprintf("Created note %d: %s", note.ID, note.Title), note)
+}
+
+// สร้าง Tool สำหรับแก้ไขบันทึก
+func CreateUpdateNoteTool() mcp.Tool {
+	return mcp.NewTool("update_note",
+		mcp.WithDescription("Replace the title and content of an existing note"),
+		destructiveTool(true),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("ID of the note to update"),
+		),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("New title of the note"),
+		),
+		mcp.WithString("content",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("New content of the note"),
+		),
+	)
+}
+
+// UpdateNoteHandler เป็นฟังก์ชันสำหรับแก้ไขบันทึก
+func (s *Server) UpdateNoteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := noteIDFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := noteInputFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.gateway.UpdateNote(ctx, creds, id, *input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult(fmt.Sprintf("Updated note %d: %s", note.ID, note.Title), note)
+}
+
+// สร้าง Tool สำหรับลบบันทึก
+func CreateDeleteNoteTool() mcp.Tool {
+	return mcp.NewTool("delete_note",
+		mcp.WithDescription("Permanently delete a note by ID"),
+		destructiveTool(true),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("ID of the note to delete"),
+		),
+	)
+}
+
+// DeleteNoteHandler เป็นฟังก์ชันสำหรับลบบันทึก
+func (s *Server) DeleteNoteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := noteIDFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.gateway.DeleteNote(ctx, creds, id); err != nil {
+		return nil, err
+	}
+
+	return toolResult(fmt.Sprintf("Note %d deleted", id), map[string]interface{}{"id": id, "deleted": true})
+}
+
+// noteIDFromRequest อ่าน id ด้วย parseNoteID จึงรับรูปแบบเดียวกับ note://{id} และ argument ของ prompt เช่น "12" หรือ "12-shopping-list"
+func noteIDFromRequest(request mcp.CallToolRequest) (uint, error) {
+	id, err := request.RequireString("id")
+	if err != nil {
+		return 0, validationError("id must be a string")
+	}
+
+	noteID, err := parseNoteID(id)
+	if err != nil {
+		return 0, validationError("invalid note ID %q", id)
+	}
+
+	return uint(noteID), nil
+}
+
+// noteInputFromRequest ตรวจสอบ title และ content ตามเงื่อนไขเดียวกับ CreateNoteRequest/UpdateNoteRequest
+func noteInputFromRequest(request mcp.CallToolRequest) (*NoteInput, error) {
+	title, err := request.RequireString("title")
+	if err != nil {
+		return nil, validationError("title must be a string")
+	}
+
+	content, err := request.RequireString("content")
+	if err != nil {
+		return nil, validationError("content must be a string")
+	}
+
+	if strings.TrimSpace(title) == "" {
+		return nil, validationError("title is required")
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, validationError("content is required")
+	}
+
+	return &NoteInput{Title: title, Content: content}, nil
+}
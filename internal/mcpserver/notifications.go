@@ -0,0 +1,394 @@
+package mcpserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// methodResourcesSubscribe และ methodResourcesUnsubscribe คือ method ที่ mcp-go ยังไม่รองรับ
+	// จึงถูกจัดการที่ชั้น transport ก่อนส่งต่อข้อความอื่นให้ MCP server
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+
+	// stdioSessionID คือ session ID ที่ server.StdioServer ใช้กับ client เพียงรายเดียวของ stdio
+	stdioSessionID = "stdio"
+)
+
+// NoteEventSource คือแหล่งเหตุการณ์การเปลี่ยนแปลงของบันทึก เช่น repository.NoteEventRepository
+type NoteEventSource interface {
+	Subscribe(ctx context.Context) (<-chan models.NoteEvent, error)
+}
+
+// subscription คือ resource ที่ client session หนึ่ง subscribe ไว้
+type subscription struct {
+	backend string
+	userID  uint64
+	uris    map[string]struct{}
+}
+
+// subscriptionStore เก็บ subscription ของแต่ละ MCP client session โดยใช้ session ID เป็น key
+type subscriptionStore struct {
+	mu   sync.RWMutex
+	subs map[string]*subscription
+}
+
+// newSubscriptionStore สร้าง subscriptionStore ใหม่
+func newSubscriptionStore() *subscriptionStore {
+	return &subscriptionStore{
+		subs: make(map[string]*subscription),
+	}
+}
+
+// add บันทึกว่า session subscribe resource ตาม URI ในนามผู้ใช้ของ credentials
+func (s *subscriptionStore) add(sessionID string, creds *Credentials, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subs[sessionID]
+	if !ok {
+		sub = &subscription{uris: make(map[string]struct{})}
+		s.subs[sessionID] = sub
+	}
+	sub.backend = creds.Backend.Name
+	sub.userID = creds.User.ID
+	sub.uris[uri] = struct{}{}
+}
+
+// remove ยกเลิก subscription ของ session สำหรับ URI
+func (s *subscriptionStore) remove(sessionID, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sub, ok := s.subs[sessionID]; ok {
+		delete(sub.uris, uri)
+		if len(sub.uris) == 0 {
+			delete(s.subs, sessionID)
+		}
+	}
+}
+
+// removeURI ยกเลิก subscription ของทุก session สำหรับ URI ที่ไม่มีอยู่แล้ว
+func (s *subscriptionStore) removeURI(backend, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sessionID, sub := range s.subs {
+		if sub.backend != backend {
+			continue
+		}
+		delete(sub.uris, uri)
+		if len(sub.uris) == 0 {
+			delete(s.subs, sessionID)
+		}
+	}
+}
+
+// deleteSession ลบ subscription ทั้งหมดของ session
+func (s *subscriptionStore) deleteSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subs, sessionID)
+}
+
+// subscribers คืน session ID ที่ subscribe URI บน backend ที่ระบุ
+func (s *subscriptionStore) subscribers(backend, uri string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []string
+	for sessionID, sub := range s.subs {
+		if _, ok := sub.uris[uri]; ok && sub.backend == backend {
+			ids = append(ids, sessionID)
+		}
+	}
+	return ids
+}
+
+// sessionIDsForUser คืน session ID ที่ subscribe resource ใด ๆ ในนามผู้ใช้ที่ระบุ
+func (s *subscriptionStore) sessionIDsForUser(backend string, userID uint64) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []string
+	for sessionID, sub := range s.subs {
+		if sub.backend == backend && sub.userID == userID {
+			ids = append(ids, sessionID)
+		}
+	}
+	return ids
+}
+
+// RunNoteNotifications รับเหตุการณ์ของบันทึกจาก source และส่ง notification ให้ client session ที่เกี่ยวข้อง
+// - notifications/resources/updated ไปยัง session ที่ subscribe note://{id}
+// - notifications/resources/list_changed ไปยัง session ของเจ้าของบันทึกเมื่อบันทึกถูกสร้างหรือลบ
+// ทำงานจนกว่า ctx จะถูกยกเลิก
+func RunNoteNotifications(ctx context.Context, s *Server, source NoteEventSource) error {
+	events, err := source.Subscribe(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to note events: %w", err)
+	}
+
+	backend := s.noteEventBackend()
+	for event := range events {
+		s.notifyNoteEvent(backend, event)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.New("note event stream closed")
+}
+
+// noteEventBackend คืนชื่อ backend ที่เหตุการณ์ของบันทึกมาจาก
+// เหตุการณ์มาจาก API ที่ใช้ Redis เดียวกัน ซึ่งคือ backend เริ่มต้นเมื่อเรียกผ่าน REST API
+func (s *Server) noteEventBackend() string {
+	if httpGateway, ok := s.gateway.(*HTTPGateway); ok {
+		return httpGateway.DefaultBackend().Name
+	}
+	return inProcessBackendName
+}
+
+// notifyNoteEvent ส่ง notification ของเหตุการณ์หนึ่งรายการ
+func (s *Server) notifyNoteEvent(backend string, event models.NoteEvent) {
+	uri := fmt.Sprintf("%s%d", noteURIScheme, event.NoteID)
+	for _, sessionID := range s.subscriptions.subscribers(backend, uri) {
+		s.sendNotification(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	}
+
+	if event.Type == models.NoteEventDeleted {
+		s.subscriptions.removeURI(backend, uri)
+	}
+	if event.Type == models.NoteEventUpdated {
+		return
+	}
+
+	userID := uint64(event.UserID)
+	notified := make(map[string]bool)
+	for _, sessionID := range append(s.sessions.sessionIDsForUser(backend, userID), s.subscriptions.sessionIDsForUser(backend, userID)...) {
+		if notified[sessionID] {
+			continue
+		}
+		notified[sessionID] = true
+		s.sendNotification(sessionID, mcp.MethodNotificationResourcesListChanged, nil)
+	}
+}
+
+// sendNotification ส่ง notification ให้ session โดยไม่ถือว่า session ที่ไม่ได้เปิด stream รับไว้เป็นข้อผิดพลาด
+func (s *Server) sendNotification(sessionID, method string, params map[string]any) {
+	err := s.SendNotificationToSpecificClient(sessionID, method, params)
+	if err != nil && !errors.Is(err, server.ErrSessionNotFound) {
+		log.Printf("Failed to send %s to session %s: %v", method, sessionID, err)
+	}
+}
+
+// subscriptionRequest คือส่วนของ JSON-RPC request ที่ใช้แยก resources/subscribe และ resources/unsubscribe
+type subscriptionRequest struct {
+	ID     mcp.RequestId `json:"id"`
+	Method string        `json:"method"`
+	Params struct {
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// isSubscriptionMethod ตรวจว่า method ต้องถูกจัดการที่ชั้น transport
+func isSubscriptionMethod(method string) bool {
+	return method == methodResourcesSubscribe || method == methodResourcesUnsubscribe
+}
+
+// handleSubscriptionMessage จัดการ resources/subscribe และ resources/unsubscribe
+// คืนค่า false เมื่อข้อความไม่ใช่ request ทั้งสองแบบ เพื่อให้ส่งต่อให้ MCP server ตามปกติ
+// JSON-RPC batch ที่มี request ทั้งสองแบบจะถูกปฏิเสธทั้ง batch เพราะคำตอบต้องรวมกับคำตอบของ MCP server ซึ่งทำไม่ได้
+func (s *Server) handleSubscriptionMessage(ctx context.Context, sessionID string, message []byte) (mcp.JSONRPCMessage, bool) {
+	trimmed := bytes.TrimSpace(message)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []subscriptionRequest
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, false
+		}
+		for _, request := range batch {
+			if isSubscriptionMethod(request.Method) {
+				return mcp.NewJSONRPCError(mcp.NewRequestId(nil), mcp.INVALID_REQUEST,
+					fmt.Sprintf("%s cannot be sent in a JSON-RPC batch", request.Method), nil), true
+			}
+		}
+		return nil, false
+	}
+
+	var request subscriptionRequest
+	if err := json.Unmarshal(trimmed, &request); err != nil || request.ID.IsNil() || !isSubscriptionMethod(request.Method) {
+		return nil, false
+	}
+
+	if !s.sessions.isRegistered(sessionID) {
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_REQUEST, "unknown or expired session", nil), true
+	}
+	// URI จาก completion เช่น note://12-shopping-list ถูกเก็บในรูป note://12 ให้ตรงกับ URI ของ notification
+	if id, err := parseNoteURI(request.Params.URI); err == nil {
+		request.Params.URI = fmt.Sprintf("%s%d", noteURIScheme, id)
+	}
+
+	var err error
+	if request.Method == methodResourcesSubscribe {
+		err = s.subscribeResource(ctx, sessionID, request.Params.URI)
+	} else {
+		s.subscriptions.remove(sessionID, request.Params.URI)
+	}
+
+	if err != nil {
+		toolErr := toToolError(err)
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, fmt.Sprintf("%s: %s", toolErr.Code, toolErr.Message), toolErr), true
+	}
+	return mcp.NewJSONRPCResultResponse(request.ID, mcp.EmptyResult{}), true
+}
+
+// subscribeResource ตรวจว่าผู้ใช้เข้าถึงบันทึกได้ก่อนบันทึก subscription
+func (s *Server) subscribeResource(ctx context.Context, sessionID, uri string) error {
+	id, err := parseNoteURI(uri)
+	if err != nil {
+		return validationError("only %s{id} resources support subscriptions", noteURIScheme)
+	}
+
+	creds, err := s.sessionCredentials(ctx, sessionID)
+	if err != nil {
+		if creds, err = s.envCredentials(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.gateway.GetNote(ctx, creds, uint(id)); err != nil {
+		return err
+	}
+
+	s.subscriptions.add(sessionID, creds, uri)
+	return nil
+}
+
+// subscriptionHandler ครอบ HTTP handler ของ streamable HTTP เพื่อตอบ resources/subscribe และ resources/unsubscribe
+func (s *Server) subscriptionHandler(next http.Handler) http.Handler {
+	s.subscribable.Store(true)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if r.Method != http.MethodPost || sessionID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		response, handled := s.handleSubscriptionMessage(r.Context(), sessionID, body)
+		if !handled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(server.HeaderKeySessionID, sessionID)
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
+
+// sseSubscriptionHandler ครอบ SSEServer เพื่อตอบ resources/subscribe และ resources/unsubscribe ที่ message endpoint
+// คำตอบถูกส่งผ่าน SSE stream ของ session และตอบ POST ด้วย 202 เช่นเดียวกับข้อความอื่นของ SSE
+func (s *Server) sseSubscriptionHandler(sseServer *server.SSEServer) http.Handler {
+	s.subscribable.Store(true)
+	messagePath := sseServer.CompleteMessagePath()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.URL.Query().Get("sessionId")
+		if r.Method != http.MethodPost || r.URL.Path != messagePath || sessionID == "" {
+			sseServer.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		response, handled := s.handleSubscriptionMessage(r.Context(), sessionID, body)
+		if !handled {
+			sseServer.ServeHTTP(w, r)
+			return
+		}
+
+		if err := sseServer.SendEventToSession(sessionID, response); err != nil {
+			// session ที่ไม่มี SSE stream เปิดอยู่รับคำตอบไม่ได้ จึงตอบกลับใน HTTP response แทน
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(response)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// syncWriter ป้องกันไม่ให้ข้อความจาก MCP server และจาก subscriptionReader เขียนปนกัน
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write เขียนข้อความหนึ่งรายการโดยถือ lock
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// subscriptionReader อ่านข้อความทีละบรรทัดจาก stdin ตอบ resources/subscribe และ resources/unsubscribe เอง
+// และส่งต่อข้อความอื่นให้ server.StdioServer ผ่าน reader ที่คืนไป
+func (s *Server) subscriptionReader(ctx context.Context, in io.Reader, out io.Writer) io.Reader {
+	s.subscribable.Store(true)
+	pr, pw := io.Pipe()
+
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response, handled := s.handleSubscriptionMessage(ctx, stdioSessionID, line); handled {
+					data, marshalErr := json.Marshal(response)
+					if marshalErr == nil {
+						_, marshalErr = out.Write(append(data, '\n'))
+					}
+					if marshalErr != nil {
+						log.Printf("Failed to write subscription response: %v", marshalErr)
+					}
+				} else if _, writeErr := pw.Write(line); writeErr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					pw.Close()
+				} else {
+					pw.CloseWithError(err)
+				}
+				return
+			}
+		}
+	}()
+
+	return pr
+}
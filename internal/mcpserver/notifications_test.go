@@ -0,0 +1,164 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestHandleSubscriptionMessage(t *testing.T) {
+	s := &Server{sessions: newSessionStore(), subscriptions: newSubscriptionStore()}
+	s.sessions.register("registered")
+	s.subscriptions.add("registered", &Credentials{}, "note://1")
+
+	cases := []struct {
+		name      string
+		sessionID string
+		message   string
+		handled   bool
+		wantError string
+	}{
+		{name: "other method", sessionID: "registered", message: `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`},
+		{name: "notification", sessionID: "registered", message: `{"jsonrpc":"2.0","method":"resources/subscribe","params":{"uri":"note://1"}}`},
+		{name: "unsubscribe", sessionID: "registered", message: `{"jsonrpc":"2.0","id":1,"method":"resources/unsubscribe","params":{"uri":"note://1"}}`, handled: true},
+		{name: "escaped method", sessionID: "registered", message: `{"jsonrpc":"2.0","id":1,"method":"resources\/unsubscribe","params":{"uri":"note://1"}}`, handled: true},
+		{name: "unknown session", sessionID: "forged", message: `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"note://1"}}`, handled: true, wantError: "unknown or expired session"},
+		{name: "batch with subscribe", sessionID: "registered", message: ` [{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"note://1"}}]`, handled: true, wantError: "cannot be sent in a JSON-RPC batch"},
+		{name: "batch without subscribe", sessionID: "registered", message: `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, handled := s.handleSubscriptionMessage(context.Background(), tc.sessionID, []byte(tc.message))
+			if handled != tc.handled {
+				t.Fatalf("handled = %v, want %v", handled, tc.handled)
+			}
+			if !handled {
+				return
+			}
+
+			data, err := json.Marshal(response)
+			if err != nil {
+				t.Fatalf("failed to marshal response: %v", err)
+			}
+			var decoded struct {
+				Error *struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("failed to decode response %s: %v", data, err)
+			}
+
+			switch {
+			case tc.wantError == "" && decoded.Error != nil:
+				t.Errorf("unexpected error response: %s", data)
+			case tc.wantError != "" && (decoded.Error == nil || !strings.Contains(decoded.Error.Message, tc.wantError)):
+				t.Errorf("response = %s, want error containing %q", data, tc.wantError)
+			}
+		})
+	}
+
+	if ids := s.subscriptions.subscribers("", "note://1"); len(ids) != 0 {
+		t.Errorf("subscribers after unsubscribe = %v, want none", ids)
+	}
+}
+
+// testSession คือ client session ที่เก็บ notification ไว้ให้ test อ่าน
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newTestSession(id string) *testSession {
+	return &testSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) SessionID() string                                   { return s.id }
+
+func TestNotifyNoteEventExternalAuth(t *testing.T) {
+	creds := func(userID uint64) *Credentials {
+		return &Credentials{Backend: Backend{Name: inProcessBackendName}, User: SessionUser{ID: userID}}
+	}
+
+	cases := []struct {
+		name string
+		// authenticate ส่ง credentials จากชั้น HTTP ให้ server ในแบบของแต่ละ transport
+		authenticate func(s *Server, session server.ClientSession)
+		want         bool
+	}{
+		{
+			name: "credentials on register",
+			authenticate: func(s *Server, session server.ClientSession) {
+				if err := s.RegisterSession(ContextWithCredentials(context.Background(), creds(1)), session); err != nil {
+					t.Fatalf("RegisterSession failed: %v", err)
+				}
+			},
+			want: true,
+		},
+		{
+			name: "credentials on a later request",
+			authenticate: func(s *Server, session server.ClientSession) {
+				if err := s.RegisterSession(context.Background(), session); err != nil {
+					t.Fatalf("RegisterSession failed: %v", err)
+				}
+				ctx := s.WithContext(ContextWithCredentials(context.Background(), creds(1)), session)
+				if _, err := s.credentialsFromContext(ctx); err != nil {
+					t.Fatalf("credentialsFromContext failed: %v", err)
+				}
+			},
+			want: true,
+		},
+		{
+			name: "credentials of another user",
+			authenticate: func(s *Server, session server.ClientSession) {
+				if err := s.RegisterSession(ContextWithCredentials(context.Background(), creds(2)), session); err != nil {
+					t.Fatalf("RegisterSession failed: %v", err)
+				}
+			},
+		},
+		{
+			name: "session ended",
+			authenticate: func(s *Server, session server.ClientSession) {
+				if err := s.RegisterSession(ContextWithCredentials(context.Background(), creds(1)), session); err != nil {
+					t.Fatalf("RegisterSession failed: %v", err)
+				}
+				s.UnregisterSession(context.Background(), session.SessionID())
+				if err := s.RegisterSession(context.Background(), session); err != nil {
+					t.Fatalf("RegisterSession failed: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := CreateServer(WithExternalAuth())
+			session := newTestSession("external")
+			tc.authenticate(s, session)
+
+			s.notifyNoteEvent(inProcessBackendName, models.NoteEvent{Type: models.NoteEventCreated, NoteID: 7, UserID: 1})
+
+			select {
+			case notification := <-session.notifications:
+				if !tc.want {
+					t.Errorf("unexpected notification %s", notification.Method)
+				} else if notification.Method != mcp.MethodNotificationResourcesListChanged {
+					t.Errorf("notification = %s, want %s", notification.Method, mcp.MethodNotificationResourcesListChanged)
+				}
+			default:
+				if tc.want {
+					t.Errorf("no %s notification", mcp.MethodNotificationResourcesListChanged)
+				}
+			}
+		})
+	}
+}
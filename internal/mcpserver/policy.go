@@ -0,0 +1,128 @@
+package mcpserver
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Policy คือข้อกำหนดว่า agent ใช้ tool ใดได้บ้าง tool ที่ไม่ผ่าน policy จะไม่ถูกลงทะเบียนเลย
+// จึงไม่แสดงใน tools/list และเรียกไม่ได้
+type Policy struct {
+	// AllowedTools ถ้าไม่ว่าง จะเปิดเฉพาะ tool ในรายการนี้
+	AllowedTools []string
+	// DisabledTools คือ tool ที่ปิดไว้ มีผลเหนือกว่า AllowedTools
+	DisabledTools []string
+	// ReadOnly เปิดเฉพาะ tool ที่มี readOnlyHint (ไม่มีการสร้าง แก้ไข หรือลบข้อมูล) และ tool ใน readOnlySessionTools
+	ReadOnly bool
+}
+
+// readOnlySessionTools คือ tool จัดการ session ที่ยังเปิดในโหมด ReadOnly
+// tool เหล่านี้ไม่ได้ทำเครื่องหมาย readOnlyHint (login บันทึกประวัติการเข้าสู่ระบบ และ logout ลบ credentials ของ session)
+// แต่ไม่แก้ไขข้อมูลของผู้ใช้ และ session ต้องล็อกอินก่อนจึงจะใช้ tool อ่านข้อมูลได้
+var readOnlySessionTools = []string{"login", "logout"}
+
+// LoadPolicyFromEnv อ่าน policy จาก environment variables
+// MCP_ALLOWED_TOOLS และ MCP_DISABLED_TOOLS เป็นรายชื่อ tool คั่นด้วย comma ส่วน MCP_READ_ONLY เป็น true/false
+func LoadPolicyFromEnv() Policy {
+	policy := Policy{
+		AllowedTools:  splitToolNames(os.Getenv("MCP_ALLOWED_TOOLS")),
+		DisabledTools: splitToolNames(os.Getenv("MCP_DISABLED_TOOLS")),
+	}
+	if v := os.Getenv("MCP_READ_ONLY"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			policy.ReadOnly = b
+		}
+	}
+	return policy
+}
+
+// Allows ตรวจว่า tool ผ่าน policy หรือไม่
+func (p Policy) Allows(tool mcp.Tool) bool {
+	if containsName(p.DisabledTools, tool.Name) {
+		return false
+	}
+	if len(p.AllowedTools) > 0 && !containsName(p.AllowedTools, tool.Name) {
+		return false
+	}
+	if p.ReadOnly && !isReadOnlyTool(tool) && !containsName(readOnlySessionTools, tool.Name) {
+		return false
+	}
+	return true
+}
+
+// warnUnknownTools เตือนเมื่อ policy อ้างถึง tool ที่ไม่มีอยู่ ซึ่งมักเกิดจากการพิมพ์ชื่อผิด
+func (p Policy) warnUnknownTools(known map[string]bool) {
+	var unknown []string
+	for _, name := range append(append([]string{}, p.AllowedTools...), p.DisabledTools...) {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		log.Printf("Warning: tool policy refers to unknown tools: %s", strings.Join(unknown, ", "))
+	}
+}
+
+// isReadOnlyTool ตรวจว่า tool ถูกทำเครื่องหมายว่าอ่านข้อมูลอย่างเดียว
+func isReadOnlyTool(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+// readOnlyTool ทำเครื่องหมายว่า tool ไม่เปลี่ยนแปลงข้อมูลในระบบ
+func readOnlyTool() mcp.ToolOption {
+	return toolAnnotations(true, false, true)
+}
+
+// additiveTool ทำเครื่องหมายว่า tool เพิ่มข้อมูลใหม่โดยไม่แก้ไขหรือลบข้อมูลเดิม
+func additiveTool() mcp.ToolOption {
+	return toolAnnotations(false, false, false)
+}
+
+// mergeTool ทำเครื่องหมายว่า tool แก้ไขเฉพาะ field ที่ส่งมาโดยคงค่าเดิมของ field อื่นไว้
+// การเรียกซ้ำด้วย argument เดิมให้ผลเหมือนเดิม
+func mergeTool() mcp.ToolOption {
+	return toolAnnotations(false, false, true)
+}
+
+// destructiveTool ทำเครื่องหมายว่า tool แก้ไขทับหรือลบข้อมูลเดิม client ควรขอยืนยันก่อนเรียก
+// idempotent ระบุว่าการเรียกซ้ำด้วย argument เดิมไม่มีผลเพิ่มเติมจากการเรียกครั้งแรก
+func destructiveTool(idempotent bool) mcp.ToolOption {
+	return toolAnnotations(false, true, idempotent)
+}
+
+// toolAnnotations กำหนด annotation ของ tool โดย tool ทั้งหมดทำงานกับข้อมูลของระบบนี้เท่านั้น (openWorldHint เป็น false)
+func toolAnnotations(readOnly, destructive, idempotent bool) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		t.Annotations.ReadOnlyHint = mcp.ToBoolPtr(readOnly)
+		t.Annotations.DestructiveHint = mcp.ToBoolPtr(destructive)
+		t.Annotations.IdempotentHint = mcp.ToBoolPtr(idempotent)
+		t.Annotations.OpenWorldHint = mcp.ToBoolPtr(false)
+	}
+}
+
+// splitToolNames แยกรายชื่อ tool ที่คั่นด้วย comma
+func splitToolNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// containsName ตรวจว่ามีชื่อในรายการหรือไม่
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// avatarResourceURI คือ URI ของ resource รูปโปรไฟล์ของผู้ใช้ที่ล็อกอิน
+	avatarResourceURI = "me://profile/avatar"
+
+	// maxProfileImageSize คือขนาดสูงสุดของรูปโปรไฟล์ (5 MB)
+	maxProfileImageSize = 5 << 20
+)
+
+// profileImageTypes คือ MIME type ที่ยอมรับและนามสกุลไฟล์ที่ใช้เมื่ออัปโหลด
+var profileImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// avatarTimeout คือเวลาสูงสุดในการดาวน์โหลดรูปโปรไฟล์
+const avatarTimeout = 10 * time.Second
+
+// สร้าง Tool สำหรับอัปโหลดรูปโปรไฟล์
+func CreateUploadProfileImageTool() mcp.Tool {
+	return mcp.NewTool("upload_profile_image",
+		mcp.WithDescription(fmt.Sprintf(
+			"Upload a new profile image for the logged-in user. Pass either image (base64 or a data: URI) or resource (an MCP blob resource). PNG, JPEG, GIF or WebP up to %d MB.",
+			maxProfileImageSize>>20,
+		)),
+		destructiveTool(false),
+		mcp.WithString("image",
+			mcp.Description("Image bytes as base64, or a data URI such as data:image/png;base64,..."),
+		),
+		mcp.WithObject("resource",
+			mcp.Description("Blob resource contents holding the image"),
+			mcp.Properties(map[string]any{
+				"uri":      map[string]any{"type": "string"},
+				"mimeType": map[string]any{"type": "string"},
+				"blob":     map[string]any{"type": "string", "description": "Base64 encoded image bytes"},
+			}),
+		),
+		mcp.WithString("filename",
+			mcp.Description("Optional file name; the extension is set from the detected image type"),
+		),
+	)
+}
+
+// UploadProfileImageHandler ตรวจสอบขนาดและชนิดของรูปก่อนอัปโหลดผ่าน gateway
+func (s *Server) UploadProfileImageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, declaredType, err := imageArgument(request)
+	if err != nil {
+		return nil, err
+	}
+
+	data, contentType, err := decodeProfileImage(encoded, declaredType)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := profileImageFilename(request.GetString("filename", ""), contentType)
+	imageURL, err := s.gateway.UploadProfileImage(ctx, creds, filename, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult(
+		fmt.Sprintf("Profile image updated: %s", imageURL),
+		map[string]interface{}{"image_url": imageURL, "mime_type": contentType, "size": len(data)},
+	)
+}
+
+// CreateAvatarResource สร้าง resource me://profile/avatar สำหรับรูปโปรไฟล์ของผู้ใช้ที่ล็อกอิน
+func CreateAvatarResource() mcp.Resource {
+	return mcp.NewResource(
+		avatarResourceURI,
+		"avatar",
+		mcp.WithResourceDescription("Current profile image of the authenticated user"),
+	)
+}
+
+// AvatarResourceHandler ดาวน์โหลดรูปโปรไฟล์จาก URL ในโปรไฟล์และคืนเป็น blob
+// ดาวน์โหลดได้เฉพาะจาก host ที่ avatarHostAllowed อนุญาต
+func (s *Server) AvatarResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.gateway.GetProfile(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	if profile.ProfileImageURL == "" {
+		return nil, newToolError(ErrCodeNotFound, "no profile image has been uploaded")
+	}
+
+	imageURL, err := s.avatarURL(creds, profile.ProfileImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, contentType, err := s.downloadProfileImage(ctx, creds, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.BlobResourceContents{
+			URI:      avatarResourceURI,
+			MIMEType: contentType,
+			Blob:     base64.StdEncoding.EncodeToString(data),
+		},
+	}, nil
+}
+
+// imageArgument อ่านรูปจาก argument image หรือ resource และคืนข้อมูล base64 กับ MIME type ที่ระบุมา (ถ้ามี)
+func imageArgument(request mcp.CallToolRequest) (string, string, error) {
+	image := strings.TrimSpace(request.GetString("image", ""))
+	resource, hasResource := request.GetArguments()["resource"].(map[string]interface{})
+
+	switch {
+	case image != "" && hasResource:
+		return "", "", validationError("pass either image or resource, not both")
+	case hasResource:
+		blob, _ := resource["blob"].(string)
+		if blob == "" {
+			return "", "", validationError("resource.blob is required")
+		}
+		mimeType, _ := resource["mimeType"].(string)
+		return blob, mimeType, nil
+	case strings.HasPrefix(image, "data:"):
+		meta, payload, ok := strings.Cut(strings.TrimPrefix(image, "data:"), ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return "", "", validationError("data URI must be base64 encoded")
+		}
+		return payload, strings.TrimSuffix(meta, ";base64"), nil
+	case image != "":
+		return image, "", nil
+	}
+	return "", "", validationError("image or resource is required")
+}
+
+// decodeProfileImage ถอดรหัส base64 และตรวจสอบขนาดและชนิดของรูปจากเนื้อหาจริง
+func decodeProfileImage(encoded, declaredType string) ([]byte, string, error) {
+	if base64.StdEncoding.DecodedLen(len(encoded)) > maxProfileImageSize+2 {
+		return nil, "", validationError("image must not exceed %d MB", maxProfileImageSize>>20)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", validationError("image is not valid base64: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, "", validationError("image is empty")
+	}
+	if len(data) > maxProfileImageSize {
+		return nil, "", validationError("image must not exceed %d MB", maxProfileImageSize>>20)
+	}
+
+	contentType, err := detectImageType(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	declaredType = strings.ToLower(strings.TrimSpace(declaredType))
+	if declaredType == "image/jpg" {
+		declaredType = "image/jpeg"
+	}
+	if declaredType != "" && declaredType != contentType {
+		return nil, "", validationError("declared MIME type %s does not match image content (%s)", declaredType, contentType)
+	}
+
+	return data, contentType, nil
+}
+
+// detectImageType ตรวจชนิดของรูปจากเนื้อหาและตรวจว่าอยู่ในชนิดที่ยอมรับ
+func detectImageType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := profileImageTypes[contentType]; !ok {
+		return "", validationError("unsupported image type %s (allowed: PNG, JPEG, GIF, WebP)", contentType)
+	}
+	return contentType, nil
+}
+
+// profileImageFilename สร้างชื่อไฟล์ที่ปลอดภัยและมีนามสกุลตรงกับชนิดของรูป
+func profileImageFilename(filename, contentType string) string {
+	name := strings.TrimSuffix(path.Base(strings.ReplaceAll(filename, "\\", "/")), path.Ext(filename))
+	if name == "" || name == "." || name == "/" {
+		name = "avatar"
+	}
+	return name + profileImageTypes[contentType]
+}
+
+// avatarURL แปลง URL ของรูปโปรไฟล์ที่ API คืนมาและตรวจ host ก่อนดาวน์โหลด เพื่อไม่ให้ server ถูกใช้เรียก host อื่น (SSRF)
+// URL แบบ relative จะถูก resolve กับ base URL ของ backend ของ session
+func (s *Server) avatarURL(creds *Credentials, raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile image URL: %v", err)
+	}
+
+	if !u.IsAbs() && creds.Backend.BaseURL != "" {
+		base, err := url.Parse(creds.Backend.BaseURL + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid backend URL: %v", err)
+		}
+		u = base.ResolveReference(u)
+	}
+
+	if !s.avatarHostAllowed(creds, u) {
+		return nil, newToolError(ErrCodeUnauthorized, "profile image host %q is not allowed", u.Host)
+	}
+	return u, nil
+}
+
+// avatarHostAllowed อนุญาตเฉพาะ host ของ backend ของ session, host ของ storage ที่ API ใช้เก็บรูป
+// (MINIO_PUBLIC_URL หรือ MINIO_ENDPOINT) และ host ใน MCP_ALLOWED_HOSTS
+func (s *Server) avatarHostAllowed(creds *Credentials, u *url.URL) bool {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+
+	for _, base := range []string{creds.Backend.BaseURL, storageBaseURL()} {
+		if baseURL, err := url.Parse(base); err == nil && base != "" && hostPort(baseURL) == hostPort(u) {
+			return true
+		}
+	}
+
+	httpGateway, ok := s.gateway.(*HTTPGateway)
+	return ok && httpGateway.backends.allowsHost(u.Hostname())
+}
+
+// storageBaseURL คืน URL สาธารณะของ storage แบบเดียวกับที่ pkg/storage ใช้สร้าง URL ของรูปโปรไฟล์
+func storageBaseURL() string {
+	if publicURL := os.Getenv("MINIO_PUBLIC_URL"); publicURL != "" {
+		return publicURL
+	}
+	if endpoint := os.Getenv("MINIO_ENDPOINT"); endpoint != "" {
+		return "http://" + endpoint
+	}
+	return ""
+}
+
+// hostPort คืน host และ port ของ URL โดยเติม port เริ่มต้นของ scheme เมื่อไม่ได้ระบุ
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return strings.ToLower(u.Hostname()) + ":" + port
+}
+
+// downloadProfileImage ดาวน์โหลดรูปโปรไฟล์โดยจำกัดขนาดและตรวจชนิดของรูป
+// redirect ทุกครั้งต้องไปยัง host ที่ avatarHostAllowed อนุญาตเช่นกัน
+func (s *Server) downloadProfileImage(ctx context.Context, creds *Credentials, imageURL *url.URL) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid profile image URL: %v", err)
+	}
+
+	client := &http.Client{
+		Timeout: avatarTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("too many redirects")
+			}
+			if !s.avatarHostAllowed(creds, req.URL) {
+				return newToolError(ErrCodeUnauthorized, "profile image redirect to host %q is not allowed", req.URL.Host)
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", newToolError(ErrCodeUpstreamUnavailable, "failed to download profile image: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", newToolError(ErrCodeNotFound, "profile image not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", newToolError(ErrCodeUpstreamUnavailable, "profile image download failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProfileImageSize+1))
+	if err != nil {
+		return nil, "", newToolError(ErrCodeUpstreamUnavailable, "failed to read profile image: %v", err)
+	}
+	if len(data) > maxProfileImageSize {
+		return nil, "", fmt.Errorf("profile image exceeds %d MB", maxProfileImageSize>>20)
+	}
+
+	contentType, err := detectImageType(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("profile image has unexpected content: %v", err)
+	}
+	return data, contentType, nil
+}
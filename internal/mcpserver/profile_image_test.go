@@ -0,0 +1,49 @@
+package mcpserver
+
+import "testing"
+
+func TestAvatarURL(t *testing.T) {
+	t.Setenv("MINIO_PUBLIC_URL", "http://storage.example.com:9000")
+
+	backends, err := NewBackends("local", map[string]Backend{
+		"local": {BaseURL: "http://api.example.com:8080"},
+	}, []string{"api.example.com", "cdn.example.com"})
+	if err != nil {
+		t.Fatalf("failed to create backends: %v", err)
+	}
+	s := &Server{gateway: NewHTTPGateway(backends)}
+	creds := &Credentials{Backend: backends.Default()}
+
+	cases := []struct {
+		name    string
+		raw     string
+		want    string
+		allowed bool
+	}{
+		{name: "backend host", raw: "http://api.example.com:8080/uploads/a.png", want: "http://api.example.com:8080/uploads/a.png", allowed: true},
+		{name: "relative to backend", raw: "/uploads/a.png", want: "http://api.example.com:8080/uploads/a.png", allowed: true},
+		{name: "storage host", raw: "http://storage.example.com:9000/profiles/a.png", want: "http://storage.example.com:9000/profiles/a.png", allowed: true},
+		{name: "allowed host", raw: "https://cdn.example.com/a.png", want: "https://cdn.example.com/a.png", allowed: true},
+		{name: "other host", raw: "http://169.254.169.254/latest/meta-data", allowed: false},
+		{name: "storage host on another port", raw: "http://storage.example.com:6379/", allowed: false},
+		{name: "non-http scheme", raw: "file:///etc/passwd", allowed: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := s.avatarURL(creds, tc.raw)
+			if !tc.allowed {
+				if err == nil {
+					t.Fatalf("avatarURL(%q) = %s, want an error", tc.raw, u)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("avatarURL(%q) failed: %v", tc.raw, err)
+			}
+			if u.String() != tc.want {
+				t.Errorf("avatarURL(%q) = %s, want %s", tc.raw, u, tc.want)
+			}
+		})
+	}
+}
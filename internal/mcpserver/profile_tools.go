@@ -0,0 +1,215 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// profileResourceURI คือ URI ของ resource โปรไฟล์ผู้ใช้ที่ล็อกอิน
+	profileResourceURI = "me://profile"
+
+	// defaultLoginHistoryLimit คือจำนวนประวัติการเข้าสู่ระบบเริ่มต้น (เท่ากับค่าเริ่มต้นของ API)
+	defaultLoginHistoryLimit = 10
+
+	// maxLoginHistoryLimit คือจำนวนประวัติการเข้าสู่ระบบสูงสุดที่ขอได้ต่อครั้ง
+	maxLoginHistoryLimit = 100
+)
+
+// genders คือค่าที่ยอมรับของ gender ตามเงื่อนไขของ ProfileUpdateRequest
+var genders = []string{"male", "female", "other"}
+
+// สร้าง Tool สำหรับดูโปรไฟล์ของผู้ใช้
+func CreateGetProfileTool() mcp.Tool {
+	return mcp.NewTool("get_profile",
+		mcp.WithDescription("Get the profile of the logged-in user (name, email, gender, roles, last login time)"),
+		readOnlyTool(),
+	)
+}
+
+// GetProfileHandler เป็นฟังก์ชันสำหรับดึงโปรไฟล์ของผู้ใช้
+func (s *Server) GetProfileHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.gateway.GetProfile(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult(renderProfileText(profile), profile)
+}
+
+// สร้าง Tool สำหรับแก้ไขโปรไฟล์ของผู้ใช้
+func CreateUpdateProfileTool() mcp.Tool {
+	return mcp.NewTool("update_profile",
+		mcp.WithDescription("Update the name and/or gender of the logged-in user; omitted fields keep their current value"),
+		mergeTool(),
+		mcp.WithString("first_name",
+			mcp.MinLength(1),
+			mcp.Description("New first name"),
+		),
+		mcp.WithString("last_name",
+			mcp.MinLength(1),
+			mcp.Description("New last name"),
+		),
+		mcp.WithString("gender",
+			mcp.Enum(genders...),
+			mcp.Description("New gender"),
+		),
+	)
+}
+
+// UpdateProfileHandler เป็นฟังก์ชันสำหรับแก้ไขโปรไฟล์
+// API ต้องการทุก field จึงเติมค่าที่ไม่ได้ระบุจากโปรไฟล์ปัจจุบันก่อนส่ง
+func (s *Server) UpdateProfileHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	firstName := strings.TrimSpace(request.GetString("first_name", ""))
+	lastName := strings.TrimSpace(request.GetString("last_name", ""))
+	gender := request.GetString("gender", "")
+	if firstName == "" && lastName == "" && gender == "" {
+		return nil, validationError("at least one of first_name, last_name or gender is required")
+	}
+
+	current, err := s.gateway.GetProfile(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	input := ProfileInput{
+		FirstName: current.FirstName,
+		LastName:  current.LastName,
+		Gender:    current.Gender,
+	}
+	if firstName != "" {
+		input.FirstName = firstName
+	}
+	if lastName != "" {
+		input.LastName = lastName
+	}
+	if gender != "" {
+		input.Gender = gender
+	}
+	if !isValidGender(input.Gender) {
+		return nil, validationError("gender must be one of %s", strings.Join(genders, ", "))
+	}
+
+	profile, err := s.gateway.UpdateProfile(ctx, creds, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toolResult("Profile updated\n\n"+renderProfileText(profile), profile)
+}
+
+// สร้าง Tool สำหรับดูประวัติการเข้าสู่ระบบ
+func CreateGetLoginHistoryTool() mcp.Tool {
+	return mcp.NewTool("get_login_history",
+		mcp.WithDescription("Get the recent logins of the logged-in user with time, IP address and user agent, newest first"),
+		readOnlyTool(),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(maxLoginHistoryLimit),
+			mcp.Description(fmt.Sprintf("Maximum number of entries to return (default %d)", defaultLoginHistoryLimit)),
+		),
+	)
+}
+
+// GetLoginHistoryHandler เป็นฟังก์ชันสำหรับดึงประวัติการเข้าสู่ระบบ
+func (s *Server) GetLoginHistoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := request.GetInt("limit", defaultLoginHistoryLimit)
+	if limit < 1 || limit > maxLoginHistoryLimit {
+		return nil, validationError("limit must be between 1 and %d", maxLoginHistoryLimit)
+	}
+
+	history, err := s.gateway.GetLoginHistory(ctx, creds, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Found %d logins", len(history))
+	for _, record := range history {
+		fmt.Fprintf(&text, "\n- %s from %s (%s)", record.LoginTime.Format(time.RFC3339), record.IPAddress, record.UserAgent)
+	}
+
+	return toolResult(text.String(), map[string]interface{}{"logins": history})
+}
+
+// CreateProfileResource สร้าง resource me://profile สำหรับโปรไฟล์ของผู้ใช้ที่ล็อกอิน
+func CreateProfileResource() mcp.Resource {
+	return mcp.NewResource(
+		profileResourceURI,
+		"profile",
+		mcp.WithResourceDescription("Profile of the authenticated user, rendered as markdown with a JSON representation"),
+		mcp.WithMIMEType("text/markdown"),
+	)
+}
+
+// ProfileResourceHandler อ่านโปรไฟล์ของผู้ใช้ที่ล็อกอิน
+func (s *Server) ProfileResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.gateway.GetProfile(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	profileJSON, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profile: %v", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      profileResourceURI,
+			MIMEType: "text/markdown",
+			Text:     "# Profile\n\n" + renderProfileText(profile) + "\n",
+		},
+		mcp.TextResourceContents{
+			URI:      profileResourceURI,
+			MIMEType: "application/json",
+			Text:     string(profileJSON),
+		},
+	}, nil
+}
+
+// renderProfileText แปลงโปรไฟล์เป็นข้อความสำหรับคนอ่าน
+func renderProfileText(profile *Profile) string {
+	lastLogin := "never"
+	if profile.LastLoginTime != nil {
+		lastLogin = profile.LastLoginTime.Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("- Name: %s %s\n- Email: %s\n- Gender: %s\n- Roles: %s\n- Last login: %s",
+		profile.FirstName, profile.LastName, profile.Email, profile.Gender, strings.Join(profile.Roles, ", "), lastLogin)
+}
+
+// isValidGender ตรวจสอบว่า gender เป็นค่าที่ API ยอมรับ
+func isValidGender(gender string) bool {
+	for _, g := range genders {
+		if g == gender {
+			return true
+		}
+	}
+	return false
+}
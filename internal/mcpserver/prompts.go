@@ -0,0 +1,171 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultSummaryDays คือจำนวนวันย้อนหลังเริ่มต้นของ prompt summarize_recent_notes
+	defaultSummaryDays = 7
+
+	// maxSummaryDays คือจำนวนวันย้อนหลังสูงสุดที่ยอมรับ
+	maxSummaryDays = 365
+)
+
+// สร้าง Prompt สำหรับสรุปบันทึกในช่วง N วันล่าสุด
+func CreateSummarizeRecentNotesPrompt() mcp.Prompt {
+	return mcp.NewPrompt("summarize_recent_notes",
+		mcp.WithPromptDescription("Summarize my notes updated in the last N days"),
+		mcp.WithArgument("days",
+			mcp.ArgumentDescription(fmt.Sprintf("Number of days to look back (default %d, max %d)", defaultSummaryDays, maxSummaryDays)),
+		),
+	)
+}
+
+// SummarizeRecentNotesHandler ดึงบันทึกที่แก้ไขภายใน N วันและแนบเป็น resource ให้โมเดลสรุป
+func (s *Server) SummarizeRecentNotesHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	days := defaultSummaryDays
+	if value := strings.TrimSpace(request.Params.Arguments["days"]); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSummaryDays {
+			return nil, validationError("days must be a number between 1 and %d", maxSummaryDays)
+		}
+		days = parsed
+	}
+
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	recent, err := s.gateway.ListNotesUpdatedSince(ctx, creds, since)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Summary of %d notes updated in the last %d days", len(recent), days)
+	if len(recent) == 0 {
+		return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(
+				fmt.Sprintf("I have no notes updated in the last %d days. Tell me so briefly.", days),
+			)),
+		}), nil
+	}
+
+	messages := []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(fmt.Sprintf(
+			"Summarize the %d notes below, which I updated in the last %d days (since %s). "+
+				"Group related notes by theme, list the key points and decisions, and call out any open tasks or follow-ups.",
+			len(recent), days, since.Format("2006-01-02"),
+		))),
+	}
+	for i := range recent {
+		messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(noteMarkdownContents(&recent[i]))))
+	}
+
+	return mcp.NewGetPromptResult(description, messages), nil
+}
+
+// สร้าง Prompt สำหรับแปลงบันทึกเป็น checklist
+func CreateNoteToChecklistPrompt() mcp.Prompt {
+	return mcp.NewPrompt("note_to_checklist",
+		mcp.WithPromptDescription("Turn a note into an actionable markdown checklist"),
+		mcp.WithArgument("id",
+			mcp.ArgumentDescription("ID of the note to convert"),
+			mcp.RequiredArgument(),
+		),
+	)
+}
+
+// NoteToChecklistHandler ดึงบันทึกตาม ID และแนบเป็น resource พร้อมคำสั่งให้แปลงเป็น checklist
+func (s *Server) NoteToChecklistHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	id, err := parseNoteID(request.Params.Arguments["id"])
+	if err != nil {
+		return nil, validationError("invalid note ID %q", request.Params.Arguments["id"])
+	}
+
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.gateway.GetNote(ctx, creds, uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	instruction := "Turn the note above into a markdown checklist of concrete, actionable items (\"- [ ] ...\"), " +
+		"keeping the original order and grouping under short headings when it helps. "
+	if s.GetTool("update_note") != nil {
+		instruction += fmt.Sprintf("Show me the checklist, and if I confirm, save it with the update_note tool using id %d.", note.ID)
+	} else {
+		instruction += "Show me the checklist so that I can copy it into the note."
+	}
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Checklist for note %d: %s", note.ID, note.Title),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(noteMarkdownContents(note))),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instruction)),
+		},
+	), nil
+}
+
+// สร้าง Prompt สำหรับร่างบันทึกจากบทสนทนา
+func CreateDraftNoteFromConversationPrompt() mcp.Prompt {
+	return mcp.NewPrompt("draft_note_from_conversation",
+		mcp.WithPromptDescription("Draft a note from the current conversation and save it"),
+		mcp.WithArgument("topic",
+			mcp.ArgumentDescription("Optional topic or title hint for the note"),
+		),
+	)
+}
+
+// DraftNoteFromConversationHandler สร้างคำสั่งให้ร่างบันทึกจากบทสนทนา
+// โดยแนบรายชื่อบันทึกที่มีอยู่เพื่อให้โมเดลเลือกแก้ไขบันทึกเดิมแทนการสร้างซ้ำ
+// prompt นี้มีเฉพาะเมื่อเปิด create_note แต่ update_note อาจถูกปิดด้วย policy จึงอ้างถึงเฉพาะเมื่อลงทะเบียนไว้
+func (s *Server) DraftNoteFromConversationHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.gateway.ListNotes(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	var instruction strings.Builder
+	instruction.WriteString("Draft a note that captures the important points, decisions and next steps of our conversation so far")
+	if topic := strings.TrimSpace(request.Params.Arguments["topic"]); topic != "" {
+		fmt.Fprintf(&instruction, ", focusing on %q", topic)
+	}
+	instruction.WriteString(". Use a short descriptive title and markdown content. " +
+		"Show me the draft first, then save it with the create_note tool once I confirm.")
+
+	if len(notes) > 0 {
+		instruction.WriteString("\n\nMy existing notes are listed below. If one already covers this topic, ")
+		if s.GetTool("update_note") != nil {
+			instruction.WriteString("propose updating it with the update_note tool instead of creating a duplicate:")
+		} else {
+			instruction.WriteString("propose changes to that note instead of creating a duplicate:")
+		}
+		for _, note := range notes {
+			fmt.Fprintf(&instruction, "\n- [%d] %s", note.ID, note.Title)
+		}
+	}
+
+	return mcp.NewGetPromptResult(
+		"Draft a note from this conversation",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instruction.String())),
+		},
+	), nil
+}
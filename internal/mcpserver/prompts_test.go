@@ -0,0 +1,120 @@
+package mcpserver
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getPrompt เรียก prompts/get และคืนข้อความทั้งหมดของ prompt ต่อกัน
+func (h *harness) getPrompt(name string, args map[string]string) (*mcp.GetPromptResult, string) {
+	h.t.Helper()
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	result, err := h.client.GetPrompt(context.Background(), request)
+	if err != nil {
+		h.t.Fatalf("prompts/get %s failed: %v", name, err)
+	}
+
+	var text strings.Builder
+	for _, message := range result.Messages {
+		if content, ok := message.Content.(mcp.TextContent); ok {
+			text.WriteString(content.Text)
+		}
+	}
+	return result, text.String()
+}
+
+func TestSummarizeRecentNotesPrompt(t *testing.T) {
+	h := newHarness(t)
+	h.login("alice@example.com", "alice-password")
+
+	before := time.Now().AddDate(0, 0, -30)
+	result, _ := h.getPrompt("summarize_recent_notes", map[string]string{"days": "30"})
+	after := time.Now().AddDate(0, 0, -30)
+
+	// ช่วงวันที่ต้องถูกส่งไปกรองที่ API แทนการดึงบันทึกทั้งหมด
+	requests := h.api.takeRequests()
+	if len(requests) != 1 {
+		t.Fatalf("API requests = %v, want a single page", requests)
+	}
+	requestURL, err := url.Parse(strings.TrimPrefix(requests[0], "GET "))
+	if err != nil {
+		t.Fatalf("invalid request %q: %v", requests[0], err)
+	}
+	query := requestURL.Query()
+	if requestURL.Path != "/api/notes" || query.Get("sort") != "updated_at" || query.Get("order") != "desc" {
+		t.Errorf("request = %s, want GET /api/notes sorted by updated_at desc", requests[0])
+	}
+	since, err := time.Parse(time.RFC3339, query.Get("updated_after"))
+	if err != nil || since.Before(before.Truncate(time.Second)) || since.After(after) {
+		t.Errorf("updated_after = %q, want 30 days ago", query.Get("updated_after"))
+	}
+
+	// ข้อความแรกคือคำสั่ง ที่เหลือคือบันทึกที่ API คืนมา
+	notes := 0
+	for _, note := range h.api.data.Notes {
+		if note.UserID == 1 && !note.UpdatedAt.Before(since) {
+			notes++
+		}
+	}
+	if len(result.Messages) != notes+1 {
+		t.Errorf("messages = %d, want the instruction and %d notes", len(result.Messages), notes)
+	}
+}
+
+func TestPromptToolReferences(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   []Option
+		prompt string
+		args   map[string]string
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "checklist with update_note",
+			prompt: "note_to_checklist",
+			args:   map[string]string{"id": "1"},
+			want:   []string{"update_note tool using id 1"},
+		},
+		{
+			name:   "draft with note tools",
+			prompt: "draft_note_from_conversation",
+			want:   []string{"create_note tool", "update_note tool", "- [1] Shopping list"},
+		},
+		{
+			name:   "draft without update_note",
+			opts:   []Option{WithPolicy(Policy{DisabledTools: []string{"update_note"}})},
+			prompt: "draft_note_from_conversation",
+			want:   []string{"create_note tool", "- [1] Shopping list"},
+			absent: []string{"update_note"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t, tc.opts...)
+			h.login("alice@example.com", "alice-password")
+
+			_, text := h.getPrompt(tc.prompt, tc.args)
+			for _, want := range tc.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("prompt is missing %q:\n%s", want, text)
+				}
+			}
+			for _, absent := range tc.absent {
+				if strings.Contains(text, absent) {
+					t.Errorf("prompt mentions %q:\n%s", absent, text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/pkg/cache"
+	"github.com/go-redis/redis/v8"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// inFlightTTL คืออายุของตัวนับ request ที่กำลังทำงานใน Redis ป้องกันตัวนับค้างเมื่อ process หยุดกลางคัน
+	inFlightTTL = 5 * time.Minute
+
+	// maxMemoryBuckets คือจำนวน bucket ใน memory ที่เริ่มล้าง bucket ที่ไม่ได้ใช้งานแล้ว
+	maxMemoryBuckets = 1024
+)
+
+// RateLimit คือ token bucket ที่เรียกได้ Requests ครั้งต่อช่วงเวลา Per และเรียกติดกันได้สูงสุด Requests ครั้ง
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// enabled ตรวจว่ามีการจำกัดอัตราหรือไม่
+func (l RateLimit) enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// rate คืนจำนวน token ที่เติมต่อวินาที
+func (l RateLimit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// String คืนค่าในรูปแบบเดียวกับ environment variable เช่น 60/1m0s
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// RateLimitConfig คือข้อจำกัดการเรียก tool ของแต่ละผู้ใช้ หรือของแต่ละ MCP session ที่ยังไม่ได้ล็อกอิน
+type RateLimitConfig struct {
+	// Session จำกัดการเรียกทุก tool รวมกัน
+	Session RateLimit
+	// Tools จำกัดการเรียกแต่ละ tool แยกกัน
+	Tools map[string]RateLimit
+	// MaxInFlight คือจำนวน tool call ที่ทำงานพร้อมกันได้สูงสุด (0 คือไม่จำกัด)
+	MaxInFlight int
+}
+
+// LoadRateLimitConfigFromEnv อ่านข้อจำกัดจาก environment variables
+//   - MCP_RATE_LIMIT เช่น 120/1m (ค่าเริ่มต้น) หรือ off
+//   - MCP_TOOL_RATE_LIMITS เช่น get_note=30/1m,create_note=10/1h
+//   - MCP_MAX_IN_FLIGHT เช่น 4 (ค่าเริ่มต้น) หรือ 0 เพื่อไม่จำกัด
+func LoadRateLimitConfigFromEnv() (RateLimitConfig, error) {
+	cfg := RateLimitConfig{
+		Session:     RateLimit{Requests: 120, Per: time.Minute},
+		Tools:       make(map[string]RateLimit),
+		MaxInFlight: 4,
+	}
+
+	if v := os.Getenv("MCP_RATE_LIMIT"); v != "" {
+		limit, err := parseRateLimit(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid MCP_RATE_LIMIT: %w", err)
+		}
+		cfg.Session = limit
+	}
+
+	for _, entry := range splitToolNames(os.Getenv("MCP_TOOL_RATE_LIMITS")) {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return cfg, fmt.Errorf("invalid MCP_TOOL_RATE_LIMITS entry %q (expected tool=requests/period)", entry)
+		}
+		limit, err := parseRateLimit(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid MCP_TOOL_RATE_LIMITS entry %q: %w", entry, err)
+		}
+		cfg.Tools[strings.TrimSpace(name)] = limit
+	}
+
+	if v := os.Getenv("MCP_MAX_IN_FLIGHT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("invalid MCP_MAX_IN_FLIGHT %q", v)
+		}
+		cfg.MaxInFlight = n
+	}
+
+	return cfg, nil
+}
+
+// parseRateLimit แปลงข้อความเช่น 30/1m หรือ 30/m เป็น RateLimit ส่วน off หรือ 0 คือไม่จำกัด
+func parseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" || value == "0" {
+		return RateLimit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("%q is not in the form requests/period", value)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 0 {
+		return RateLimit{}, fmt.Errorf("invalid number of requests %q", requests)
+	}
+
+	period = strings.TrimSpace(period)
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	per, err := time.ParseDuration(period)
+	if err != nil || per <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid period %q", period)
+	}
+
+	return RateLimit{Requests: n, Per: per}, nil
+}
+
+// RateLimitStore เก็บสถานะของ token bucket และจำนวน tool call ที่กำลังทำงาน
+type RateLimitStore interface {
+	// Take ใช้ token หนึ่งตัวจาก bucket ของ key และคืนเวลาที่ต้องรอเมื่อ token หมด (0 คือเรียกได้)
+	Take(ctx context.Context, key string, limit RateLimit) (time.Duration, error)
+	// Acquire เพิ่มจำนวน tool call ที่กำลังทำงานของ key หากยังไม่เกิน max
+	Acquire(ctx context.Context, key string, max int) (bool, error)
+	// Release ลดจำนวน tool call ที่กำลังทำงานของ key
+	Release(ctx context.Context, key string) error
+}
+
+// tokenBucket คือสถานะของ bucket ใน memory
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryRateLimitStore เก็บสถานะใน memory ของ process เหมาะกับ stdio ที่มี client เดียว
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	inFlight map[string]int
+}
+
+// NewMemoryRateLimitStore สร้าง MemoryRateLimitStore ใหม่
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:  make(map[string]*tokenBucket),
+		inFlight: make(map[string]int),
+	}
+}
+
+// Take ใช้ token หนึ่งตัวจาก bucket ของ key
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.buckets) > maxMemoryBuckets {
+		s.pruneBuckets(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Requests), last: now}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(float64(limit.Requests), bucket.tokens+now.Sub(bucket.last).Seconds()*limit.rate())
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) / limit.rate() * float64(time.Second)), nil
+	}
+	bucket.tokens--
+	return 0, nil
+}
+
+// pruneBuckets ลบ bucket ที่ไม่ได้ใช้งานนานจนเติม token เต็มแล้ว ซึ่งมีสถานะเหมือน bucket ใหม่
+func (s *MemoryRateLimitStore) pruneBuckets(now time.Time) {
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.last) > time.Hour {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Acquire เพิ่มจำนวน tool call ที่กำลังทำงานของ key หากยังไม่เกิน max
+func (s *MemoryRateLimitStore) Acquire(ctx context.Context, key string, max int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inFlight[key] >= max {
+		return false, nil
+	}
+	s.inFlight[key]++
+	return true, nil
+}
+
+// Release ลดจำนวน tool call ที่กำลังทำงานของ key
+func (s *MemoryRateLimitStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inFlight[key] <= 1 {
+		delete(s.inFlight, key)
+	} else {
+		s.inFlight[key]--
+	}
+	return nil
+}
+
+// takeTokenScript คือ token bucket ที่ทำงานแบบ atomic ใน Redis คืนจำนวน millisecond ที่ต้องรอ
+var takeTokenScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / 1000 * rate)
+local wait = 0
+if tokens < 1 then
+  wait = math.ceil((1 - tokens) / rate * 1000)
+else
+  tokens = tokens - 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate * 1000) + 1000)
+return wait
+`)
+
+// acquireScript เพิ่มตัวนับ tool call ที่กำลังทำงานหากยังไม่เกินค่าสูงสุด คืน 1 เมื่อสำเร็จ
+var acquireScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+if n > tonumber(ARGV[1]) then
+  redis.call('DECR', KEYS[1])
+  return 0
+end
+return 1
+`)
+
+// releaseScript ลดตัวนับ tool call ที่กำลังทำงานเฉพาะเมื่อ key ยังอยู่ ไม่ให้ต่ำกว่า 0 และคงอายุเดิมของ key
+// DECR เฉยๆ หลัง key หมดอายุจะสร้าง key ใหม่ที่ค่า -1 และไม่มีอายุ
+var releaseScript = redis.NewScript(`
+local n = tonumber(redis.call('GET', KEYS[1]))
+if not n then
+  return 0
+end
+if n <= 1 then
+  redis.call('DEL', KEYS[1])
+  return 0
+end
+return redis.call('DECR', KEYS[1])
+`)
+
+// RedisRateLimitStore เก็บสถานะใน Redis เพื่อให้ข้อจำกัดมีผลร่วมกันทุก instance ของ transport แบบ network
+type RedisRateLimitStore struct {
+	redisClient *cache.RedisClient
+}
+
+// NewRedisRateLimitStore สร้าง RedisRateLimitStore ใหม่
+func NewRedisRateLimitStore(redisClient *cache.RedisClient) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		redisClient: redisClient,
+	}
+}
+
+// Take ใช้ token หนึ่งตัวจาก bucket ของ key
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (time.Duration, error) {
+	wait, err := takeTokenScript.Run(ctx, s.redisClient.Client, []string{"mcp:ratelimit:" + key},
+		limit.Requests, limit.rate(), time.Now().UnixMilli()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+// Acquire เพิ่มจำนวน tool call ที่กำลังทำงานของ key หากยังไม่เกิน max
+func (s *RedisRateLimitStore) Acquire(ctx context.Context, key string, max int) (bool, error) {
+	ok, err := acquireScript.Run(ctx, s.redisClient.Client, []string{"mcp:inflight:" + key},
+		max, inFlightTTL.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return ok == 1, nil
+}
+
+// Release ลดจำนวน tool call ที่กำลังทำงานของ key
+func (s *RedisRateLimitStore) Release(ctx context.Context, key string) error {
+	return releaseScript.Run(ctx, s.redisClient.Client, []string{"mcp:inflight:" + key}).Err()
+}
+
+// rateLimitedError สร้าง ToolError ที่บอก client ว่าควรรอนานเท่าใดก่อนเรียกใหม่
+func rateLimitedError(retryAfter time.Duration, format string, args ...interface{}) *ToolError {
+	toolErr := newToolError(ErrCodeRateLimited, format, args...)
+	toolErr.RetryAfter = int(math.Ceil(retryAfter.Seconds()))
+	if toolErr.RetryAfter < 1 {
+		toolErr.RetryAfter = 1
+	}
+	toolErr.Message = fmt.Sprintf("%s, retry after %d seconds", toolErr.Message, toolErr.RetryAfter)
+	return toolErr
+}
+
+// rateLimitMiddleware จำกัดอัตราและจำนวน tool call พร้อมกันของแต่ละผู้ใช้
+// หาก store ใช้งานไม่ได้จะยอมให้เรียกต่อ (fail open) เพื่อไม่ให้ Redis ล่มแล้ว tool ใช้ไม่ได้ทั้งหมด
+func (s *Server) rateLimitMiddleware(store RateLimitStore, cfg RateLimitConfig) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			key := s.rateLimitKey(ctx)
+			tool := request.Params.Name
+
+			if cfg.Session.enabled() {
+				if wait := takeToken(ctx, store, key, cfg.Session); wait > 0 {
+					return nil, rateLimitedError(wait, "rate limit of %s exceeded", cfg.Session)
+				}
+			}
+
+			if limit, ok := cfg.Tools[tool]; ok && limit.enabled() {
+				if wait := takeToken(ctx, store, key+":"+tool, limit); wait > 0 {
+					return nil, rateLimitedError(wait, "rate limit of %s for %s exceeded", limit, tool)
+				}
+			}
+
+			if cfg.MaxInFlight > 0 {
+				ok, err := store.Acquire(ctx, key, cfg.MaxInFlight)
+				if err != nil {
+					log.Printf("Warning: rate limit store unavailable: %v", err)
+				} else if !ok {
+					return nil, rateLimitedError(time.Second, "too many tool calls in progress (max %d per user)", cfg.MaxInFlight)
+				} else {
+					defer func() {
+						if err := store.Release(context.Background(), key); err != nil {
+							log.Printf("Warning: failed to release in-flight tool call: %v", err)
+						}
+					}()
+				}
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// rateLimitKey คืน key ของ bucket ที่ request นี้ใช้
+// ผู้ใช้ที่ยืนยันตัวตนแล้วใช้ bucket เดียวกันทุก session เพื่อไม่ให้หลบข้อจำกัดด้วยการเริ่ม session ใหม่
+// ส่วน request ที่ยังไม่ได้ล็อกอินใช้ bucket ของ session
+func (s *Server) rateLimitKey(ctx context.Context) string {
+	if creds, err := s.credentialsFromContext(ctx); err == nil {
+		return fmt.Sprintf("user:%s:%d", creds.Backend.Name, creds.User.ID)
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return "session:" + session.SessionID()
+	}
+	return "anonymous"
+}
+
+// takeToken ใช้ token จาก store และคืนเวลาที่ต้องรอ โดยถือว่าเรียกได้เมื่อ store มีปัญหา
+func takeToken(ctx context.Context, store RateLimitStore, key string, limit RateLimit) time.Duration {
+	wait, err := store.Take(ctx, key, limit)
+	if err != nil {
+		log.Printf("Warning: rate limit store unavailable: %v", err)
+		return 0
+	}
+	return wait
+}
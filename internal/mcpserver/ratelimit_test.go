@@ -0,0 +1,199 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestMemoryRateLimitStoreTake(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Requests: 2, Per: time.Minute}
+
+	for i := 0; i < limit.Requests; i++ {
+		if wait, err := store.Take(context.Background(), "a", limit); err != nil || wait != 0 {
+			t.Fatalf("take %d = %v, %v, want no wait", i+1, wait, err)
+		}
+	}
+	wait, err := store.Take(context.Background(), "a", limit)
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	// bucket เติม token หนึ่งตัวทุก 30 วินาที
+	if wait <= 29*time.Second || wait > 30*time.Second {
+		t.Errorf("wait of an empty bucket = %v, want about 30s", wait)
+	}
+
+	if wait, _ := store.Take(context.Background(), "b", limit); wait != 0 {
+		t.Errorf("wait of another key = %v, want no wait", wait)
+	}
+}
+
+func TestMemoryRateLimitStoreInFlight(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := store.Acquire(ctx, "a", 2); !ok {
+			t.Fatalf("acquire %d was rejected", i+1)
+		}
+	}
+	if ok, _ := store.Acquire(ctx, "a", 2); ok {
+		t.Errorf("acquire above the maximum was allowed")
+	}
+	if ok, _ := store.Acquire(ctx, "b", 2); !ok {
+		t.Errorf("acquire of another key was rejected")
+	}
+
+	_ = store.Release(ctx, "a")
+	if ok, _ := store.Acquire(ctx, "a", 2); !ok {
+		t.Errorf("acquire after release was rejected")
+	}
+
+	// Release ที่เกินจำนวน acquire ต้องไม่ทำให้ตัวนับติดลบ
+	for i := 0; i < 5; i++ {
+		_ = store.Release(ctx, "a")
+	}
+	for i := 0; i < 2; i++ {
+		if ok, _ := store.Acquire(ctx, "a", 2); !ok {
+			t.Fatalf("acquire %d after extra releases was rejected", i+1)
+		}
+	}
+	if ok, _ := store.Acquire(ctx, "a", 2); ok {
+		t.Errorf("extra releases raised the maximum")
+	}
+}
+
+// rateLimitedCall เรียก handler ผ่าน middleware และคืน ToolError เมื่อถูกจำกัด
+func rateLimitedCall(t *testing.T, handler server.ToolHandlerFunc, ctx context.Context, tool string) *ToolError {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool
+
+	_, err := handler(ctx, request)
+	if err == nil {
+		return nil
+	}
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("error = %v, want a ToolError", err)
+	}
+	return toolErr
+}
+
+func TestRateLimitMiddlewareBuckets(t *testing.T) {
+	s := &Server{sessions: newSessionStore()}
+	user := func(id uint64) *Credentials {
+		return &Credentials{Backend: Backend{Name: inProcessBackendName}, User: SessionUser{ID: id}}
+	}
+	sessionCtx := func(sessionID string, creds *Credentials) context.Context {
+		ctx := context.Background()
+		if creds != nil {
+			ctx = ContextWithCredentials(ctx, creds)
+		}
+		return (&server.MCPServer{}).WithContext(ctx, newTestSession(sessionID))
+	}
+
+	cases := []struct {
+		name   string
+		first  context.Context
+		second context.Context
+		shared bool
+	}{
+		{name: "same user in two sessions", first: sessionCtx("a", user(1)), second: sessionCtx("b", user(1)), shared: true},
+		{name: "two users", first: sessionCtx("a", user(1)), second: sessionCtx("b", user(2))},
+		{name: "same anonymous session", first: sessionCtx("a", nil), second: sessionCtx("a", nil), shared: true},
+		{name: "two anonymous sessions", first: sessionCtx("a", nil), second: sessionCtx("b", nil)},
+		{name: "session before and after login", first: sessionCtx("a", nil), second: sessionCtx("a", user(1))},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := RateLimitConfig{Session: RateLimit{Requests: 1, Per: time.Minute}}
+			handler := s.rateLimitMiddleware(NewMemoryRateLimitStore(), cfg)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			if toolErr := rateLimitedCall(t, handler, tc.first, "get_note"); toolErr != nil {
+				t.Fatalf("first call was limited: %v", toolErr)
+			}
+			toolErr := rateLimitedCall(t, handler, tc.second, "get_note")
+			if limited := toolErr != nil; limited != tc.shared {
+				t.Errorf("second call limited = %v, want %v", limited, tc.shared)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddlewareErrors(t *testing.T) {
+	s := &Server{sessions: newSessionStore()}
+	ctx := (&server.MCPServer{}).WithContext(
+		ContextWithCredentials(context.Background(), &Credentials{Backend: Backend{Name: inProcessBackendName}, User: SessionUser{ID: 1}}),
+		newTestSession("a"))
+
+	t.Run("tool limit", func(t *testing.T) {
+		cfg := RateLimitConfig{Tools: map[string]RateLimit{"create_note": {Requests: 1, Per: 10 * time.Second}}}
+		handler := s.rateLimitMiddleware(NewMemoryRateLimitStore(), cfg)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		if toolErr := rateLimitedCall(t, handler, ctx, "create_note"); toolErr != nil {
+			t.Fatalf("first call was limited: %v", toolErr)
+		}
+		if toolErr := rateLimitedCall(t, handler, ctx, "get_note"); toolErr != nil {
+			t.Errorf("another tool was limited: %v", toolErr)
+		}
+
+		toolErr := rateLimitedCall(t, handler, ctx, "create_note")
+		if toolErr == nil {
+			t.Fatalf("second call was not limited")
+		}
+		if toolErr.Code != ErrCodeRateLimited || toolErr.RetryAfter != 10 {
+			t.Errorf("error = %+v, want %s with retry after 10 seconds", toolErr, ErrCodeRateLimited)
+		}
+		data, _ := json.Marshal(toolErr)
+		if !strings.Contains(string(data), `"retry_after_seconds":10`) {
+			t.Errorf("error JSON = %s, want retry_after_seconds", data)
+		}
+	})
+
+	t.Run("in-flight cap", func(t *testing.T) {
+		cfg := RateLimitConfig{MaxInFlight: 1}
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := s.rateLimitMiddleware(NewMemoryRateLimitStore(), cfg)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Name == "slow" {
+				close(started)
+				<-release
+			}
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		done := make(chan error)
+		go func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = "slow"
+			_, err := handler(ctx, request)
+			done <- err
+		}()
+		<-started
+
+		toolErr := rateLimitedCall(t, handler, ctx, "get_note")
+		if toolErr == nil || toolErr.Code != ErrCodeRateLimited || toolErr.RetryAfter != 1 {
+			t.Errorf("call while another is in progress = %+v, want %s with retry after 1 second", toolErr, ErrCodeRateLimited)
+		}
+
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("slow call failed: %v", err)
+		}
+		if toolErr := rateLimitedCall(t, handler, ctx, "get_note"); toolErr != nil {
+			t.Errorf("call after the slot was released was limited: %v", toolErr)
+		}
+	})
+}
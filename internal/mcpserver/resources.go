@@ -0,0 +1,122 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// noteURIScheme คือ prefix ของ URI สำหรับ resource บันทึก
+const noteURIScheme = "note://"
+
+// CreateNoteResourceTemplate สร้าง resource template สำหรับบันทึกในรูปแบบ note://{id}
+func CreateNoteResourceTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		noteURIScheme+"{id}",
+		"note",
+		mcp.WithTemplateDescription("A note of the authenticated user, rendered as markdown with a JSON representation"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+}
+
+// NoteResourceHandler อ่านบันทึกตาม note://{id} ผ่าน notes API
+// โดยใช้ credentials ของ session ที่ล็อกอินไว้ หรือ backend เริ่มต้นกับ MCP_API_TOKEN จาก environment
+func (s *Server) NoteResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+
+	id, err := parseNoteURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := s.resourceCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.gateway.GetNote(ctx, creds, uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	noteJSON, err := json.MarshalIndent(note, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal note: %v", err)
+	}
+
+	// ทั้งสองรูปแบบใช้ URI มาตรฐานของบันทึกแม้ client จะอ่านด้วย URI รูปแบบอื่น เช่น note://12-slug
+	markdown := noteMarkdownContents(note)
+	return []mcp.ResourceContents{
+		markdown,
+		mcp.TextResourceContents{
+			URI:      markdown.URI,
+			MIMEType: "application/json",
+			Text:     string(noteJSON),
+		},
+	}, nil
+}
+
+// noteMarkdownContents สร้าง resource contents แบบ markdown ของบันทึก
+func noteMarkdownContents(note *Note) mcp.TextResourceContents {
+	return mcp.TextResourceContents{
+		URI:      fmt.Sprintf("%s%d", noteURIScheme, note.ID),
+		MIMEType: "text/markdown",
+		Text:     renderNoteMarkdown(note),
+	}
+}
+
+// resourceCredentials เลือก credentials ของ session ก่อน แล้วจึงใช้ค่าจาก environment
+func (s *Server) resourceCredentials(ctx context.Context) (*Credentials, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if err == nil {
+		return creds, nil
+	}
+	return s.envCredentials()
+}
+
+// envCredentials สร้าง credentials ของ backend เริ่มต้นจาก MCP_API_TOKEN
+func (s *Server) envCredentials() (*Credentials, error) {
+	// MCP_API_TOKEN ใช้ได้เฉพาะเมื่อเรียกผ่าน REST API
+	httpGateway, ok := s.gateway.(*HTTPGateway)
+	token := os.Getenv("MCP_API_TOKEN")
+	if !ok || token == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	return &Credentials{Backend: httpGateway.DefaultBackend(), Token: token}, nil
+}
+
+// parseNoteURI แยก ID ของบันทึกออกจาก URI รูปแบบ note://{id}
+func parseNoteURI(uri string) (uint64, error) {
+	if !strings.HasPrefix(uri, noteURIScheme) {
+		return 0, fmt.Errorf("invalid note URI: %s", uri)
+	}
+
+	id, err := parseNoteID(strings.TrimPrefix(uri, noteURIScheme))
+	if err != nil {
+		return 0, fmt.Errorf("invalid note ID in URI: %s", uri)
+	}
+
+	return id, nil
+}
+
+// parseNoteID แปลง ID ของบันทึกจาก URI หรือ argument id ของ prompt และ tool ซึ่งเป็น "12" หรือค่าจาก completion เช่น "12-shopping-list"
+// ส่วนหลัง - เป็นชื่อบันทึกที่ช่วยให้ผู้ใช้เลือกได้ง่ายเท่านั้นจึงไม่ถูกตรวจ
+func parseNoteID(value string) (uint64, error) {
+	idPart, _, _ := strings.Cut(strings.TrimSpace(value), "-")
+	id, err := strconv.ParseUint(idPart, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid note ID %q", value)
+	}
+	return id, nil
+}
+
+// renderNoteMarkdown แปลงบันทึกเป็น markdown สำหรับแนบเป็น context
+func renderNoteMarkdown(note *Note) string {
+	return fmt.Sprintf("# %s\n\n%s\n", note.Title, note.Content)
+}
@@ -0,0 +1,35 @@
+package mcpserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNoteResourceURI(t *testing.T) {
+	h := newHarness(t)
+	h.login("alice@example.com", "alice-password")
+
+	for _, uri := range []string{"note://1", "note://1-shopping-list"} {
+		t.Run(uri, func(t *testing.T) {
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = uri
+
+			result, err := h.client.ReadResource(context.Background(), request)
+			if err != nil {
+				t.Fatalf("resources/read %s failed: %v", uri, err)
+			}
+			if len(result.Contents) != 2 {
+				t.Fatalf("contents = %d, want markdown and JSON", len(result.Contents))
+			}
+			// ทั้ง markdown และ JSON ต้องใช้ URI มาตรฐานของบันทึก
+			for _, content := range result.Contents {
+				text, ok := content.(mcp.TextResourceContents)
+				if !ok || text.URI != "note://1" {
+					t.Errorf("%s contents URI = %q, want note://1", text.MIMEType, text.URI)
+				}
+			}
+		})
+	}
+}
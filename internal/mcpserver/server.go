@@ -1,54 +1,241 @@
+// Package mcpserver ให้บริการ MCP server ของระบบบันทึก ประกอบด้วย tool, resource และ prompt
+// ที่เข้าถึงข้อมูลผ่าน Gateway ในนามผู้ใช้ที่ล็อกอิน
+//
+// audit log (ดู WithAuditLogger และ WithAuditStore) บันทึกเฉพาะการเรียก tool เท่านั้น
+// การอ่าน resource, prompt และ completion ไม่ถูกบันทึก
 package mcpserver
 
 import (
-	"context"
+	"sync/atomic"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
 )
 
-func CreateDocTool() mcp.Tool {
-	return mcp.NewTool("doc",
-		mcp.WithDescription("แสดงเอกสารการใช้งาน MCP Server"),
-	)
+const (
+	// serverName และ serverVersion คือชื่อและเวอร์ชันที่ประกาศใน initialize และแสดงในเอกสาร
+	serverName    = "MCPServerSample"
+	serverVersion = "1.0.0"
+)
+
+// Option คือตัวเลือกสำหรับการสร้าง MCP server
+type Option func(*options)
+
+// options คือการตั้งค่าที่รวบรวมจาก Option ทั้งหมด
+type options struct {
+	gateway      IGateway
+	externalAuth bool
+	policy       Policy
+	auditLogger  *zap.Logger
+	auditStore   AuditStore
+	rateStore    RateLimitStore
+	rateLimits   RateLimitConfig
 }
 
-// DocHandler เป็นฟังก์ชันสำหรับจัดการคำขอ /doc เพื่อแสดงเอกสารการใช้งานของเซิร์ฟเวอร์
-func DocHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	documentation := `MCPServer Sample Documentation
+// WithBackends ให้ tool เรียก REST API ของชุด backend ที่กำหนดผ่าน HTTPGateway
+func WithBackends(b *Backends) Option {
+	return WithGateway(NewHTTPGateway(b))
+}
 
-เครื่องมือที่มีให้ใช้งาน:
-- login: ใช้สำหรับล็อกอินและรับ token
-    พารามิเตอร์: base_url, email, password
-- visitor_count: สำหรับแสดงจำนวนผู้เข้าชม
-    พารามิเตอร์: base_url
-- note: สำหรับดึงข้อมูลบันทึกตาม ID (dynamic resource)
-    รูปแบบ: note://{id}
-    พารามิเตอร์: base_url, token
-- doc: แสดงเอกสารการใช้งาน MCP Server
-`
-	return mcp.NewToolResultText(documentation), nil
+// WithGateway กำหนดช่องทางที่ tool ใช้เข้าถึงข้อมูล เช่น ServiceGateway สำหรับโหมด in-process
+func WithGateway(g IGateway) Option {
+	return func(o *options) {
+		o.gateway = g
+	}
 }
 
-func CreateServer() *server.MCPServer {
+// WithExternalAuth ใช้เมื่อผู้ใช้ยืนยันตัวตนมาแล้วจากชั้น HTTP (ดู NewHTTPHandler)
+// โดยจะไม่ลงทะเบียน tool login และ logout
+func WithExternalAuth() Option {
+	return func(o *options) {
+		o.externalAuth = true
+	}
+}
 
-	s := server.NewMCPServer(
-		"MCPServerSample",
-		"1.0.0",
+// WithPolicy กำหนด policy ที่จำกัด tool ที่ลงทะเบียน เช่น LoadPolicyFromEnv()
+func WithPolicy(p Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithAuditLogger บันทึกการเรียก tool ทุกครั้ง (ชื่อ tool, session, ผู้ใช้, argument ที่ปิดค่าลับแล้ว, ระยะเวลา และผลลัพธ์) ผ่าน logger
+func WithAuditLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.auditLogger = logger
+	}
+}
+
+// WithAuditStore บันทึกการเรียก tool ลงที่เก็บถาวร เช่น ตาราง audit_logs ผ่าน repository.AuditLogRepository
+func WithAuditStore(store AuditStore) Option {
+	return func(o *options) {
+		o.auditStore = store
+	}
+}
+
+// WithRateLimit จำกัดอัตราการเรียก tool ต่อผู้ใช้ (หรือต่อ session ก่อนล็อกอิน) และต่อ tool รวมถึงจำนวน tool call ที่ทำงานพร้อมกัน
+// ใช้ MemoryRateLimitStore กับ stdio และ RedisRateLimitStore กับ transport แบบ network
+func WithRateLimit(store RateLimitStore, cfg RateLimitConfig) Option {
+	return func(o *options) {
+		o.rateStore = store
+		o.rateLimits = cfg
+	}
+}
+
+// Server คือ MCP server หนึ่งตัวพร้อม state ของตัวเอง (gateway, credentials ของ session และ subscription)
+// handler ทุกตัวเป็น method ของ Server จึงไม่มี state ที่ใช้ร่วมกันระหว่าง server หลายตัวในกระบวนการเดียวกัน
+type Server struct {
+	*server.MCPServer
+	gateway       IGateway
+	sessions      *sessionStore
+	subscriptions *subscriptionStore
+	// externalAuth คือ WithExternalAuth ซึ่งผู้ใช้มาจาก JWT ของ HTTP request แทน tool login
+	externalAuth bool
+	// subscribable ถูกตั้งเมื่อ transport ที่จัดการ resources/subscribe ถูกสร้างให้ server นี้
+	subscribable atomic.Bool
+}
+
+// CreateServer สร้าง MCP server พร้อมลงทะเบียน tools และ resources ทั้งหมด
+func CreateServer(opts ...Option) *Server {
+	o := &options{gateway: NewHTTPGateway(defaultBackends())}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	srv := &Server{
+		gateway:       o.gateway,
+		sessions:      newSessionStore(),
+		subscriptions: newSubscriptionStore(),
+		externalAuth:  o.externalAuth,
+	}
+	completions := noteCompletionProvider{server: srv}
+
+	serverOpts := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithToolFilter(srv.adminToolFilter),
 		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(true),
+		server.WithCompletions(),
+		server.WithResourceCompletionProvider(completions),
+		server.WithPromptCompletionProvider(completions),
+		server.WithHooks(srv.sessionHooks()),
+		server.WithToolHandlerMiddleware(toolErrorMiddleware),
+	}
+	if o.auditLogger != nil || o.auditStore != nil {
+		if o.auditLogger == nil {
+			o.auditLogger = zap.NewNop()
+		}
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(srv.auditMiddleware(o.auditLogger, o.auditStore)))
+	}
+	// rate limit อยู่ในสุดเพื่อให้ audit log บันทึกการเรียกที่ถูกปฏิเสธด้วย
+	if o.rateStore != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(srv.rateLimitMiddleware(o.rateStore, o.rateLimits)))
+	}
+
+	s := server.NewMCPServer(
+		serverName,
+		serverVersion,
+		serverOpts...,
 	)
+	srv.MCPServer = s
+
+	// addTool ลงทะเบียนเฉพาะ tool ที่ผ่าน policy
+	known := make(map[string]bool)
+	registered := make(map[string]bool)
+	addTool := func(tool mcp.Tool, handler server.ToolHandlerFunc) {
+		known[tool.Name] = true
+		if o.policy.Allows(tool) {
+			registered[tool.Name] = true
+			s.AddTool(tool, handler)
+		}
+	}
 
-	loginTool := CreateLoginTool()
-	s.AddTool(loginTool, LoginHandler)
+	if !o.externalAuth {
+		loginTool := CreateLoginTool(srv.gateway.Backends())
+		addTool(loginTool, srv.LoginHandler)
 
-	visitorTool := CreateVisitorCountTool()
-	s.AddTool(visitorTool, VisitorCountHandler)
+		logoutTool := CreateLogoutTool()
+		addTool(logoutTool, srv.LogoutHandler)
+	}
+
+	whoAmITool := CreateWhoAmITool()
+	addTool(whoAmITool, srv.WhoAmIHandler)
+
+	visitorTool := CreateVisitorCountTool(srv.gateway.Backends())
+	addTool(visitorTool, srv.VisitorCountHandler)
 
 	noteTool := CreateGetNoteTool()
-	s.AddTool(noteTool, GetNoteHandler)
+	addTool(noteTool, srv.GetNoteHandler)
+
+	listNotesTool := CreateListNotesTool()
+	addTool(listNotesTool, srv.ListNotesHandler)
+
+	createNoteTool := CreateCreateNoteTool()
+	addTool(createNoteTool, srv.CreateNoteHandler)
+
+	updateNoteTool := CreateUpdateNoteTool()
+	addTool(updateNoteTool, srv.UpdateNoteHandler)
+
+	deleteNoteTool := CreateDeleteNoteTool()
+	addTool(deleteNoteTool, srv.DeleteNoteHandler)
+
+	getProfileTool := CreateGetProfileTool()
+	addTool(getProfileTool, srv.GetProfileHandler)
+
+	updateProfileTool := CreateUpdateProfileTool()
+	addTool(updateProfileTool, srv.UpdateProfileHandler)
+
+	loginHistoryTool := CreateGetLoginHistoryTool()
+	addTool(loginHistoryTool, srv.GetLoginHistoryHandler)
+
+	uploadImageTool := CreateUploadProfileImageTool()
+	addTool(uploadImageTool, srv.UploadProfileImageHandler)
+
+	listUsersTool := CreateListUsersTool()
+	addTool(listUsersTool, srv.ListUsersHandler)
+
+	deactivateUserTool := CreateDeactivateUserTool()
+	addTool(deactivateUserTool, srv.DeactivateUserHandler)
+
+	changeRoleTool := CreateChangeUserRoleTool()
+	addTool(changeRoleTool, srv.ChangeUserRoleHandler)
+
+	userLoginHistoryTool := CreateGetUserLoginHistoryTool()
+	addTool(userLoginHistoryTool, srv.GetUserLoginHistoryHandler)
 
 	docTool := CreateDocTool()
-	s.AddTool(docTool, DocHandler)
+	addTool(docTool, srv.DocHandler)
+
+	o.policy.warnUnknownTools(known)
+
+	noteTemplate := CreateNoteResourceTemplate()
+	s.AddResourceTemplate(noteTemplate, srv.NoteResourceHandler)
+
+	profileResource := CreateProfileResource()
+	s.AddResource(profileResource, srv.ProfileResourceHandler)
+
+	avatarResource := CreateAvatarResource()
+	s.AddResource(avatarResource, srv.AvatarResourceHandler)
+
+	for _, lang := range docLanguages {
+		docResource := CreateDocResource(lang)
+		s.AddResource(docResource, srv.DocResourceHandler)
+	}
+
+	summarizePrompt := CreateSummarizeRecentNotesPrompt()
+	s.AddPrompt(summarizePrompt, srv.SummarizeRecentNotesHandler)
+
+	// prompt ที่สั่งให้ agent เขียนบันทึกจะมีเฉพาะเมื่อ tool ที่ใช้ถูกเปิดไว้
+	if registered["update_note"] {
+		checklistPrompt := CreateNoteToChecklistPrompt()
+		s.AddPrompt(checklistPrompt, srv.NoteToChecklistHandler)
+	}
+
+	if registered["create_note"] {
+		draftPrompt := CreateDraftNoteFromConversationPrompt()
+		s.AddPrompt(draftPrompt, srv.DraftNoteFromConversationHandler)
+	}
 
-	return s
+	return srv
 }
@@ -0,0 +1,179 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolsList(t *testing.T) {
+	h := newHarness(t)
+	assertGolden(t, "tools_list", h.listTools())
+
+	h.login("admin@example.com", "admin-password")
+	assertGolden(t, "tools_list_admin", h.listTools())
+}
+
+func TestToolsListReadOnly(t *testing.T) {
+	h := newHarness(t, WithPolicy(Policy{ReadOnly: true}))
+
+	names := []string{}
+	for _, tool := range h.listTools() {
+		names = append(names, tool.Name)
+	}
+	assertGolden(t, "tools_list_read_only", names)
+}
+
+func TestDocumentation(t *testing.T) {
+	docText := func(h *harness) string {
+		t.Helper()
+		result := h.callTool("doc", map[string]interface{}{"lang": "en"})
+		if result.IsError || len(result.Content) == 0 {
+			t.Fatalf("doc failed: %+v", result)
+		}
+		text, _ := result.Content[0].(mcp.TextContent)
+		return text.Text
+	}
+
+	h := newHarness(t)
+	doc := docText(h)
+	for _, want := range []string{docTexts["en"].noteLogin, docTexts["en"].noteCompletion} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("documentation is missing %q", want)
+		}
+	}
+	// in-process transport ไม่ได้ผ่าน subscriptionHandler จึงต้องไม่อ้างว่ารองรับ resources/subscribe
+	for _, unwanted := range []string{"list_users", docTexts["en"].adminTools, docTexts["en"].noteSubscribe, docTexts["en"].noteExternalAuth} {
+		if strings.Contains(doc, unwanted) {
+			t.Errorf("documentation for a non-admin session contains %q", unwanted)
+		}
+	}
+
+	h.login("admin@example.com", "admin-password")
+	if doc := docText(h); !strings.Contains(doc, "### list_users") {
+		t.Errorf("documentation for an admin session is missing the admin tools")
+	}
+}
+
+func TestNoteCompletion(t *testing.T) {
+	h := newHarness(t)
+	h.login("alice@example.com", "alice-password")
+
+	cases := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "all notes", value: "", want: []string{"2-project-ideas", "1-shopping-list"}},
+		{name: "title prefix", value: "shop", want: []string{"1-shopping-list"}},
+		{name: "title prefix ignores case", value: "PROJ", want: []string{"2-project-ideas"}},
+		{name: "text inside the title", value: "ideas", want: []string{}},
+		{name: "id", value: "1", want: []string{"1-shopping-list"}},
+		{name: "completed value", value: "2-project-ideas", want: []string{"2-project-ideas"}},
+		{name: "other user's note", value: "3", want: []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := mcp.CompleteRequest{}
+			request.Params.Ref = mcp.ResourceReference{Type: "ref/resource", URI: noteURIScheme + "{id}"}
+			request.Params.Argument = mcp.CompleteArgument{Name: noteIDArgument, Value: tc.value}
+
+			result, err := h.client.Complete(context.Background(), request)
+			if err != nil {
+				t.Fatalf("completion/complete failed: %v", err)
+			}
+			if strings.Join(result.Completion.Values, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("values = %v, want %v", result.Completion.Values, tc.want)
+			}
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	resources, err := h.client.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		t.Fatalf("resources/list failed: %v", err)
+	}
+	templates, err := h.client.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("resources/templates/list failed: %v", err)
+	}
+	prompts, err := h.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		t.Fatalf("prompts/list failed: %v", err)
+	}
+
+	assertGolden(t, "catalog", map[string]interface{}{
+		"resources":          resources.Resources,
+		"resource_templates": templates.ResourceTemplates,
+		"prompts":            prompts.Prompts,
+	})
+}
+
+// toolCase คือการเรียก tool หนึ่งครั้ง ผลลัพธ์และ request ที่ fake API ได้รับถูกเทียบกับ testdata/golden/tools/{name}.golden
+type toolCase struct {
+	name     string
+	email    string
+	password string
+	tool     string
+	args     map[string]interface{}
+}
+
+func TestToolCalls(t *testing.T) {
+	const (
+		alice    = "alice@example.com"
+		alicePwd = "alice-password"
+		admin    = "admin@example.com"
+		adminPwd = "admin-password"
+	)
+
+	cases := []toolCase{
+		{name: "whoami_logged_out", tool: "whoami"},
+		{name: "whoami", email: alice, password: alicePwd, tool: "whoami"},
+		{name: "login_invalid", tool: "login", args: map[string]interface{}{"email": alice, "password": "wrong"}},
+		{name: "login_inactive", tool: "login", args: map[string]interface{}{"email": "bob@example.com", "password": "bob-password"}},
+		{name: "get_visitor_count", tool: "get_visitor_count"},
+		{name: "list_notes_logged_out", tool: "list_notes"},
+		{name: "list_notes", email: alice, password: alicePwd, tool: "list_notes"},
+		{name: "get_note", email: alice, password: alicePwd, tool: "get_note", args: map[string]interface{}{"id": "1"}},
+		{name: "get_note_not_found", email: alice, password: alicePwd, tool: "get_note", args: map[string]interface{}{"id": "3"}},
+		{name: "get_note_invalid_id", email: alice, password: alicePwd, tool: "get_note", args: map[string]interface{}{"id": "abc"}},
+		{name: "get_note_completion_value", email: alice, password: alicePwd, tool: "get_note", args: map[string]interface{}{"id": "1-shopping-list"}},
+		{name: "create_note", email: alice, password: alicePwd, tool: "create_note", args: map[string]interface{}{"title": "Reading list", "content": "- Go in Action"}},
+		{name: "update_note", email: alice, password: alicePwd, tool: "update_note", args: map[string]interface{}{"id": "2", "title": "Project ideas", "content": "Ship the MCP server."}},
+		{name: "delete_note", email: alice, password: alicePwd, tool: "delete_note", args: map[string]interface{}{"id": "1"}},
+		{name: "get_profile", email: alice, password: alicePwd, tool: "get_profile"},
+		{name: "update_profile_invalid_gender", email: alice, password: alicePwd, tool: "update_profile", args: map[string]interface{}{"first_name": "Alice", "last_name": "Smith", "gender": "unknown"}},
+		{name: "get_login_history", email: alice, password: alicePwd, tool: "get_login_history", args: map[string]interface{}{"limit": 5}},
+		{name: "list_users_forbidden", email: alice, password: alicePwd, tool: "list_users"},
+		{name: "list_users", email: admin, password: adminPwd, tool: "list_users"},
+		{name: "change_user_role", email: admin, password: adminPwd, tool: "change_user_role", args: map[string]interface{}{"user_id": 1, "roles": []string{"User", "Staff"}}},
+		{name: "deactivate_user_self", email: admin, password: adminPwd, tool: "deactivate_user", args: map[string]interface{}{"user_id": 2}},
+		{name: "deactivate_user_super_admin", email: admin, password: adminPwd, tool: "deactivate_user", args: map[string]interface{}{"user_id": 4}},
+		{name: "get_user_login_history", email: admin, password: adminPwd, tool: "get_user_login_history", args: map[string]interface{}{"user_id": 1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// แต่ละกรณีมี server และ fake API ของตัวเองจึงทำงานพร้อมกันได้
+			t.Parallel()
+
+			h := newHarness(t)
+			if tc.email != "" {
+				h.login(tc.email, tc.password)
+			}
+
+			result := h.callTool(tc.tool, tc.args)
+			assertGolden(t, "tools/"+tc.name, map[string]interface{}{
+				"api_requests": h.api.takeRequests(),
+				"result":       result,
+			})
+		})
+	}
+}
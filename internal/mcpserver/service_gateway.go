@@ -0,0 +1,480 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/internal/repository"
+	"github.com/Napat/mcpserver-demo/internal/service"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/Napat/mcpserver-demo/pkg/middleware"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// inProcessBackendName คือชื่อ backend ของ ServiceGateway
+const inProcessBackendName = "in-process"
+
+// ServiceGateway เข้าถึงข้อมูลผ่าน service layer โดยตรง ไม่มีการเรียกผ่าน network
+type ServiceGateway struct {
+	userService    service.IUserService
+	noteService    service.INoteService
+	visitorService service.IVisitorService
+	logger         *zap.Logger
+}
+
+// NewServiceGateway สร้าง instance ใหม่ของ ServiceGateway
+func NewServiceGateway(userService service.IUserService, noteService service.INoteService, visitorService service.IVisitorService, logger *zap.Logger) *ServiceGateway {
+	return &ServiceGateway{
+		userService:    userService,
+		noteService:    noteService,
+		visitorService: visitorService,
+		logger:         logger,
+	}
+}
+
+// Backends คืนค่าว่าง เพราะ ServiceGateway ไม่มี backend ให้เลือก
+func (g *ServiceGateway) Backends() []string {
+	return nil
+}
+
+// Login ตรวจสอบอีเมลและรหัสผ่านผ่าน IUserService และบันทึกประวัติการเข้าสู่ระบบ
+func (g *ServiceGateway) Login(ctx context.Context, backendName, email, password string) (*Credentials, error) {
+	if backendName != "" && backendName != inProcessBackendName {
+		return nil, validationError("backend selection is not available in in-process mode")
+	}
+
+	user, err := g.userService.Login(email, password)
+	if err != nil {
+		g.logger.Error("Failed to login", zap.Error(err))
+		return nil, newToolError(ErrCodeUnauthorized, "invalid credentials")
+	}
+
+	if err := g.userService.RecordLogin(uint(user.ID), "", "mcpserver"); err != nil {
+		g.logger.Error("Failed to record login history", zap.Error(err))
+	}
+
+	token, err := middleware.GenerateToken(uint(user.ID), user.Role)
+	if err != nil {
+		g.logger.Error("Failed to generate token", zap.Error(err))
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &Credentials{
+		Backend: Backend{Name: inProcessBackendName},
+		Token:   token,
+		User:    sessionUserFromModel(user),
+	}, nil
+}
+
+// GetVisitorCount ดึงจำนวนผู้เข้าชมผ่าน IVisitorService
+func (g *ServiceGateway) GetVisitorCount(ctx context.Context, backendName string) (int64, error) {
+	return g.visitorService.GetVisitorCount(ctx)
+}
+
+// ListNotes ดึงรายการบันทึกของผู้ใช้ผ่าน INoteService
+func (g *ServiceGateway) ListNotes(ctx context.Context, creds *Credentials) ([]Note, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := g.noteService.GetAllByUserID(userID)
+	if err != nil {
+		return nil, noteServiceError(err)
+	}
+
+	result := make([]Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, *noteFromModel(&notes[i]))
+	}
+	return result, nil
+}
+
+// ListNotesUpdatedSince ดึงบันทึกทุกหน้าผ่าน INoteService.ListByUserID
+func (g *ServiceGateway) ListNotesUpdatedSince(ctx context.Context, creds *Credentials, since time.Time) ([]Note, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	params := service.NoteListParams{
+		Filter:     repository.NoteFilter{UpdatedAfter: &since},
+		SortBy:     repository.NoteSortUpdatedAt,
+		Descending: true,
+		Limit:      service.MaxNotePageSize,
+	}
+	result := []Note{}
+	for {
+		page, err := g.noteService.ListByUserID(userID, params)
+		if err != nil {
+			return nil, noteServiceError(err)
+		}
+		for i := range page.Notes {
+			result = append(result, *noteFromModel(&page.Notes[i]))
+		}
+		if page.NextCursor == "" {
+			return result, nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
+
+// FindNotesByTitlePrefix ดึงบันทึกหน้าเดียวผ่าน INoteService.ListByUserID
+func (g *ServiceGateway) FindNotesByTitlePrefix(ctx context.Context, creds *Credentials, prefix string, limit int) ([]Note, int64, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, err := g.noteService.ListByUserID(userID, service.NoteListParams{
+		Filter:     repository.NoteFilter{TitlePrefix: prefix},
+		SortBy:     repository.NoteSortUpdatedAt,
+		Descending: true,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, 0, noteServiceError(err)
+	}
+
+	result := make([]Note, 0, len(page.Notes))
+	for i := range page.Notes {
+		result = append(result, *noteFromModel(&page.Notes[i]))
+	}
+	return result, page.Total, nil
+}
+
+// GetNote ดึงบันทึกผ่าน INoteService ซึ่งตรวจสอบสิทธิ์การเข้าถึงให้
+func (g *ServiceGateway) GetNote(ctx context.Context, creds *Credentials, id uint) (*Note, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := g.noteService.GetByID(id, userID)
+	if err != nil {
+		return nil, noteServiceError(err)
+	}
+	return noteFromModel(note), nil
+}
+
+// CreateNote สร้างบันทึกผ่าน INoteService
+func (g *ServiceGateway) CreateNote(ctx context.Context, creds *Credentials, input NoteInput) (*Note, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	note := models.Note{
+		Title:   input.Title,
+		Content: input.Content,
+		UserID:  userID,
+	}
+	if err := g.noteService.Create(&note); err != nil {
+		return nil, noteServiceError(err)
+	}
+	return noteFromModel(&note), nil
+}
+
+// UpdateNote แก้ไขบันทึกผ่าน INoteService
+func (g *ServiceGateway) UpdateNote(ctx context.Context, creds *Credentials, id uint, input NoteInput) (*Note, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	note := models.Note{
+		ID:      id,
+		Title:   input.Title,
+		Content: input.Content,
+		UserID:  userID,
+	}
+	if err := g.noteService.Update(&note, userID); err != nil {
+		return nil, noteServiceError(err)
+	}
+	return noteFromModel(&note), nil
+}
+
+// DeleteNote ลบบันทึกผ่าน INoteService
+func (g *ServiceGateway) DeleteNote(ctx context.Context, creds *Credentials, id uint) error {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return err
+	}
+	return noteServiceError(g.noteService.Delete(id, userID))
+}
+
+// GetProfile ดึงโปรไฟล์ผ่าน IUserService
+func (g *ServiceGateway) GetProfile(ctx context.Context, creds *Credentials) (*Profile, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := g.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+	return profileFromModel(user), nil
+}
+
+// UpdateProfile แก้ไขชื่อและเพศของผู้ใช้ผ่าน IUserService เช่นเดียวกับ UserHandler.UpdateProfile
+func (g *ServiceGateway) UpdateProfile(ctx context.Context, creds *Credentials, input ProfileInput) (*Profile, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := g.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	user.FirstName = input.FirstName
+	user.LastName = input.LastName
+	user.Gender = input.Gender
+	if err := g.userService.UpdateProfile(user); err != nil {
+		return nil, err
+	}
+	return profileFromModel(user), nil
+}
+
+// GetLoginHistory ดึงประวัติการเข้าสู่ระบบผ่าน IUserService
+func (g *ServiceGateway) GetLoginHistory(ctx context.Context, creds *Credentials, limit int) ([]LoginRecord, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := g.userService.GetLoginHistory(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return loginRecordsFromModel(history), nil
+}
+
+// UploadProfileImage อัปโหลดรูปโปรไฟล์ผ่าน IUserService ซึ่งรับไฟล์ในรูปแบบ multipart.FileHeader
+func (g *ServiceGateway) UploadProfileImage(ctx context.Context, creds *Credentials, filename, contentType string, data []byte) (string, error) {
+	userID, err := userIDFromCredentials(creds)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := fileHeaderFromBytes(filename, contentType, data)
+	if err != nil {
+		return "", err
+	}
+
+	imageURL, err := g.userService.UpdateProfileImage(userID, file)
+	if err != nil {
+		return "", userServiceError(err)
+	}
+	return imageURL, nil
+}
+
+// ListUsers ดึงรายชื่อผู้ใช้ทั้งหมดผ่าน IUserService
+func (g *ServiceGateway) ListUsers(ctx context.Context, creds *Credentials) ([]UserAccount, error) {
+	if err := requireAdmin(creds); err != nil {
+		return nil, err
+	}
+
+	users, err := g.userService.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	return userAccountsFromModel(users), nil
+}
+
+// DeactivateUser ปิดการใช้งานผู้ใช้ผ่าน IUserService
+func (g *ServiceGateway) DeactivateUser(ctx context.Context, creds *Credentials, id uint) (*UserAccount, error) {
+	if err := requireAdmin(creds); err != nil {
+		return nil, err
+	}
+
+	user, err := g.userService.DeactivateUser(id, uint(creds.User.ID), models.UserRole(creds.User.Role))
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+	return userAccountFromModel(user), nil
+}
+
+// ChangeUserRole เปลี่ยนบทบาทของผู้ใช้ผ่าน IUserService
+func (g *ServiceGateway) ChangeUserRole(ctx context.Context, creds *Credentials, id uint, role uint8) (*UserAccount, error) {
+	if err := requireAdmin(creds); err != nil {
+		return nil, err
+	}
+
+	user, err := g.userService.ChangeRole(id, models.UserRole(role), uint(creds.User.ID), models.UserRole(creds.User.Role))
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+	return userAccountFromModel(user), nil
+}
+
+// GetUserLoginHistory ดึงประวัติการเข้าสู่ระบบของผู้ใช้ใดก็ได้ผ่าน IUserService
+func (g *ServiceGateway) GetUserLoginHistory(ctx context.Context, creds *Credentials, id uint, limit int) ([]LoginRecord, error) {
+	if err := requireAdmin(creds); err != nil {
+		return nil, err
+	}
+
+	history, err := g.userService.GetLoginHistory(id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return loginRecordsFromModel(history), nil
+}
+
+// fileHeaderFromBytes สร้าง multipart.FileHeader จากข้อมูลไฟล์ในหน่วยความจำ
+// โดยเขียนเป็น multipart form แล้วอ่านกลับ เพราะ FileHeader สร้างได้จากการ parse form เท่านั้น
+func fileHeaderFromBytes(filename, contentType string, data []byte) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="image"; filename=%q`, filename))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create multipart body: %w", err)
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(len(data)) + 1024)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read multipart body: %w", err)
+	}
+	return form.File["image"][0], nil
+}
+
+// noteServiceError แปลง error ของ INoteService เป็น ToolError ตามเงื่อนไขเดียวกับ NoteHandler
+func noteServiceError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err.Error() == "unauthorized access to note":
+		return newToolError(ErrCodeUnauthorized, "Access denied")
+	case err.Error() == "note not found" || errors.Is(err, gorm.ErrRecordNotFound):
+		return newToolError(ErrCodeNotFound, "Note not found")
+	}
+	return err
+}
+
+// userServiceError แปลง error ของ IUserService เป็น ToolError ตามเงื่อนไขเดียวกับ AdminHandler
+func userServiceError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch err.Error() {
+	case "user not found":
+		return newToolError(ErrCodeNotFound, "User not found")
+	case "user is inactive":
+		return validationError("User is inactive")
+	case "invalid role", "cannot deactivate your own account", "cannot remove your own admin roles", "cannot remove the last super admin":
+		return validationError("%s", err.Error())
+	case "only super admins can manage super admins":
+		return newToolError(ErrCodeUnauthorized, "%s", err.Error())
+	}
+	return err
+}
+
+// userIDFromCredentials ดึง user ID จาก credentials
+func userIDFromCredentials(creds *Credentials) (uint, error) {
+	if creds == nil || creds.User.ID == 0 {
+		return 0, ErrNotLoggedIn
+	}
+	return uint(creds.User.ID), nil
+}
+
+// noteFromModel แปลง models.Note เป็น Note ของ MCP
+func noteFromModel(note *models.Note) *Note {
+	return &Note{
+		ID:        int(note.ID),
+		Title:     note.Title,
+		Content:   note.Content,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+}
+
+// profileFromModel แปลง models.User เป็น Profile
+func profileFromModel(user *models.User) *Profile {
+	roles := user.Role.GetRoleNames()
+	sort.Strings(roles)
+
+	return &Profile{
+		ID:              user.ID,
+		Email:           user.Email,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Gender:          user.Gender,
+		Role:            uint8(user.Role),
+		Roles:           roles,
+		ProfileImageURL: user.ProfileImageURL,
+		LastLoginTime:   user.LastLoginTime,
+		CreatedAt:       user.CreatedAt,
+	}
+}
+
+// userAccountFromModel แปลง models.User เป็น UserAccount
+func userAccountFromModel(user *models.User) *UserAccount {
+	roles := user.Role.GetRoleNames()
+	sort.Strings(roles)
+
+	return &UserAccount{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Role:          uint8(user.Role),
+		Roles:         roles,
+		Active:        user.Active,
+		LastLoginTime: user.LastLoginTime,
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+// userAccountsFromModel แปลงรายชื่อ models.User เป็น UserAccount
+func userAccountsFromModel(users []models.User) []UserAccount {
+	accounts := make([]UserAccount, 0, len(users))
+	for i := range users {
+		accounts = append(accounts, *userAccountFromModel(&users[i]))
+	}
+	return accounts
+}
+
+// loginRecordsFromModel แปลง models.LoginHistory เป็น LoginRecord
+func loginRecordsFromModel(history []models.LoginHistory) []LoginRecord {
+	records := make([]LoginRecord, 0, len(history))
+	for _, h := range history {
+		records = append(records, LoginRecord{
+			LoginTime: h.LoginTime,
+			IPAddress: h.IPAddress,
+			UserAgent: h.UserAgent,
+		})
+	}
+	return records
+}
+
+// sessionUserFromModel แปลง models.User เป็น SessionUser
+func sessionUserFromModel(user *models.User) SessionUser {
+	return SessionUser{
+		ID:        user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Role:      uint8(user.Role),
+	}
+}
@@ -0,0 +1,191 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrNotLoggedIn ถูกคืนเมื่อ session ปัจจุบันยังไม่ได้ล็อกอิน
+var ErrNotLoggedIn = errors.New("not logged in: call the login tool first")
+
+// SessionUser คือข้อมูลผู้ใช้ที่ล็อกอินอยู่ใน session
+type SessionUser struct {
+	ID        uint64 `json:"id"`
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Role      uint8  `json:"role"`
+}
+
+// sessionStore เก็บ credentials ของแต่ละ MCP client session โดยใช้ session ID เป็น key
+type sessionStore struct {
+	mu    sync.RWMutex
+	creds map[string]*Credentials
+	// registered คือ session ที่ลงทะเบียนกับ MCP server แล้วและยังไม่สิ้นสุด
+	registered map[string]bool
+	// users คือผู้ใช้ของ session ที่ยืนยันตัวตนจากชั้น HTTP (external auth) ซึ่งไม่ได้เก็บ credentials ไว้ใน session
+	users map[string]sessionUserKey
+}
+
+// sessionUserKey ระบุผู้ใช้บน backend หนึ่ง
+type sessionUserKey struct {
+	backend string
+	userID  uint64
+}
+
+// newSessionStore สร้าง sessionStore ใหม่
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		creds:      make(map[string]*Credentials),
+		registered: make(map[string]bool),
+		users:      make(map[string]sessionUserKey),
+	}
+}
+
+// register บันทึกว่า session ลงทะเบียนกับ MCP server แล้ว
+func (s *sessionStore) register(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registered[sessionID] = true
+}
+
+// unregister ลบ session ที่สิ้นสุดแล้วพร้อม credentials ของ session
+func (s *sessionStore) unregister(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.registered, sessionID)
+	delete(s.creds, sessionID)
+	delete(s.users, sessionID)
+}
+
+// recordUser บันทึกผู้ใช้ของ credentials ที่ยืนยันตัวตนจากชั้น HTTP ให้กับ session ที่ลงทะเบียนแล้ว
+// เพื่อให้ส่ง notification ถึง session นั้นได้แม้ไม่ได้ล็อกอินผ่าน tool login
+func (s *sessionStore) recordUser(sessionID string, creds *Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.registered[sessionID] {
+		return
+	}
+	s.users[sessionID] = sessionUserKey{backend: creds.Backend.Name, userID: creds.User.ID}
+}
+
+// isRegistered ตรวจว่า session ID เป็นของ session ที่ลงทะเบียนกับ MCP server และยังไม่สิ้นสุด
+func (s *sessionStore) isRegistered(sessionID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.registered[sessionID]
+}
+
+// set บันทึก credentials ของ session
+func (s *sessionStore) set(sessionID string, creds *Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creds[sessionID] = creds
+}
+
+// get ดึง credentials ของ session
+func (s *sessionStore) get(sessionID string) (*Credentials, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	creds, ok := s.creds[sessionID]
+	return creds, ok
+}
+
+// delete ลบ credentials ของ session และคืนค่าว่าเคยมีอยู่หรือไม่
+func (s *sessionStore) delete(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.creds[sessionID]
+	delete(s.creds, sessionID)
+	return ok
+}
+
+// sessionIDsForUser คืน session ID ทั้งหมดที่ล็อกอินหรือยืนยันตัวตนจากชั้น HTTP เป็นผู้ใช้ที่ระบุบน backend ที่ระบุ
+func (s *sessionStore) sessionIDsForUser(backend string, userID uint64) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := sessionUserKey{backend: backend, userID: userID}
+	var ids []string
+	for sessionID, creds := range s.creds {
+		if creds.Backend.Name == backend && creds.User.ID == userID {
+			ids = append(ids, sessionID)
+		}
+	}
+	for sessionID, user := range s.users {
+		if _, ok := s.creds[sessionID]; !ok && user == key {
+			ids = append(ids, sessionID)
+		}
+	}
+	return ids
+}
+
+// sessionIDFromContext ดึง session ID ของ MCP client จาก context
+func sessionIDFromContext(ctx context.Context) (string, error) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return "", errors.New("no MCP client session in context")
+	}
+	return session.SessionID(), nil
+}
+
+// credentialsContextKey คือ key ของ credentials ที่ยืนยันตัวตนมาแล้วจากชั้น HTTP
+type credentialsContextKey struct{}
+
+// ContextWithCredentials แนบ credentials ที่ยืนยันตัวตนแล้ว (เช่นจาก JWT) ไปกับ context ของ request
+// credentials นี้มีผลเหนือกว่า credentials ที่เก็บไว้ใน session
+func ContextWithCredentials(ctx context.Context, creds *Credentials) context.Context {
+	return context.WithValue(ctx, credentialsContextKey{}, creds)
+}
+
+// credentialsFromContext ดึง credentials ของ request จาก context หรือของ session ปัจจุบัน
+// credentials จาก context จะถูกบันทึกเป็นผู้ใช้ของ session เพื่อใช้ส่ง notification
+func (s *Server) credentialsFromContext(ctx context.Context) (*Credentials, error) {
+	if creds, ok := ctx.Value(credentialsContextKey{}).(*Credentials); ok && creds != nil {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			s.sessions.recordUser(session.SessionID(), creds)
+		}
+		return creds, nil
+	}
+
+	sessionID, err := sessionIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionCredentials(ctx, sessionID)
+}
+
+// sessionCredentials ดึง credentials ของ request จาก context หรือของ session ที่ระบุ
+// ใช้กับข้อความที่ถูกจัดการก่อนถึง MCP server ซึ่งยังไม่มี client session ใน context
+func (s *Server) sessionCredentials(ctx context.Context, sessionID string) (*Credentials, error) {
+	if creds, ok := ctx.Value(credentialsContextKey{}).(*Credentials); ok && creds != nil {
+		s.sessions.recordUser(sessionID, creds)
+		return creds, nil
+	}
+
+	creds, ok := s.sessions.get(sessionID)
+	if !ok {
+		return nil, ErrNotLoggedIn
+	}
+	return creds, nil
+}
+
+// sessionHooks คืน hooks ที่ติดตาม session ที่ลงทะเบียนกับ MCP server
+// และล้าง credentials และ subscription เมื่อ client session สิ้นสุด
+func (s *Server) sessionHooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.sessions.register(session.SessionID())
+		if creds, ok := ctx.Value(credentialsContextKey{}).(*Credentials); ok && creds != nil {
+			s.sessions.recordUser(session.SessionID(), creds)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.sessions.unregister(session.SessionID())
+		s.subscriptions.deleteSession(session.SessionID())
+	})
+	return hooks
+}
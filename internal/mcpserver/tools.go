@@ -2,42 +2,18 @@ package mcpserver
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
-	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-// LoginResponse คือโครงสร้างสำหรับข้อมูล response จากการล็อกอิน
-type LoginResponse struct {
-	Token string `json:"token"`
-}
-
-// Note คือโครงสร้างสำหรับข้อมูลบันทึก
-type Note struct {
-	ID      int    `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-}
-
-// VisitorResponse คือโครงสร้างสำหรับข้อมูลจำนวนผู้เข้าชม
-type VisitorResponse struct {
-	Count int `json:"visitor_count"`
-}
-
-// สร้าง Tool สำหรับการล็อกอิน
-func CreateLoginTool() mcp.Tool {
+// สร้าง Tool สำหรับการล็อกอิน โดย backends คือชื่อ backend ที่เลือกได้จาก IGateway.Backends
+func CreateLoginTool(backends []string) mcp.Tool {
 	return mcp.NewTool("login",
-		mcp.WithDescription("Login to the API and get a token"),
-		mcp.WithString("base_url",
-			mcp.Required(),
-			mcp.Description("Base URL of the API (e.g., http://localhost:8001)"),
-		),
+		mcp.WithDescription("Login to the API; the session stays authenticated for later tools"),
+		additiveTool(),
+		withBackendArgument(backends),
 		mcp.WithString("email",
 			mcp.Required(),
 			mcp.Description("Email for login"),
@@ -50,134 +26,119 @@ func CreateLoginTool() mcp.Tool {
 }
 
 // LoginHandler เป็นฟังก์ชันสำหรับจัดการคำขอล็อกอิน
-func LoginHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	baseURL, ok := request.Params.Arguments["base_url"].(string)
+func (s *Server) LoginHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	email, ok := request.GetArguments()["email"].(string)
 	if !ok {
-		return nil, errors.New("base_url must be a string")
+		return nil, validationError("email must be a string")
 	}
 
-	email, ok := request.Params.Arguments["email"].(string)
+	password, ok := request.GetArguments()["password"].(string)
 	if !ok {
-		return nil, errors.New("email must be a string")
+		return nil, validationError("password must be a string")
 	}
 
-	password, ok := request.Params.Arguments["password"].(string)
-	if !ok {
-		return nil, errors.New("password must be a string")
-	}
-
-	// ตัดเครื่องหมาย / ถ้ามีที่ท้าย baseURL
-	baseURL = strings.TrimSuffix(baseURL, "/")
-
-	// สร้าง HTTP client และส่ง request
-	loginURL := fmt.Sprintf("%s/api/auth/login", baseURL)
-	payload := fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, password)
-
-	// สร้าง HTTP client ที่มีการตั้งค่า timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	sessionID, err := sessionIDFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", loginURL, strings.NewReader(payload))
+	creds, err := s.gateway.Login(ctx, request.GetString("backend", ""), email, password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	// เพิ่ม header
-	req.Header.Set("Content-Type", "application/json")
+	// เก็บ token ไว้ใน session โดยไม่ส่งกลับไปให้โมเดล
+	previous, _ := s.sessions.get(sessionID)
+	s.sessions.set(sessionID, creds)
+	notifyToolListChanged(ctx, previous, creds)
 
-	// ส่ง request
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("login request failed: %v", err)
-	}
-	defer resp.Body.Close()
+	return toolResult(
+		fmt.Sprintf("Logged in to %s as %s", creds.Backend.Name, creds.User.Email),
+		map[string]interface{}{"backend": creds.Backend.Name, "user": creds.User},
+	)
+}
+
+// สร้าง Tool สำหรับออกจากระบบ
+func CreateLogoutTool() mcp.Tool {
+	return mcp.NewTool("logout",
+		mcp.WithDescription("Logout and forget the credentials of the current session"),
+		destructiveTool(false),
+	)
+}
 
-	// อ่าน response
-	body, err := io.ReadAll(resp.Body)
+// LogoutHandler เป็นฟังก์ชันสำหรับลบ credentials ของ session ปัจจุบัน
+func (s *Server) LogoutHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, err := sessionIDFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("login failed with status %d: %s", resp.StatusCode, string(body))
+	previous, _ := s.sessions.get(sessionID)
+	if !s.sessions.delete(sessionID) {
+		return toolResult("Not logged in", map[string]interface{}{"logged_out": false})
 	}
+	notifyToolListChanged(ctx, previous, nil)
 
-	// แปลง response เป็น struct
-	var loginResp LoginResponse
-	if err := json.Unmarshal(body, &loginResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
-	}
+	return toolResult("Logged out", map[string]interface{}{"logged_out": true})
+}
 
-	if loginResp.Token == "" {
-		return nil, errors.New("no token received in response")
+// สร้าง Tool สำหรับดูผู้ใช้ที่ล็อกอินอยู่
+func CreateWhoAmITool() mcp.Tool {
+	return mcp.NewTool("whoami",
+		mcp.WithDescription("Show the user the current session is logged in as"),
+		readOnlyTool(),
+	)
+}
+
+// WhoAmIHandler เป็นฟังก์ชันสำหรับแสดงข้อมูลผู้ใช้ของ session ปัจจุบัน
+func (s *Server) WhoAmIHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
+	if errors.Is(err, ErrNotLoggedIn) {
+		return toolResult("Not logged in", map[string]interface{}{"logged_in": false})
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// ส่งคืนผลลัพธ์เป็น text แทน JSON
-	return mcp.NewToolResultText(fmt.Sprintf("Token: %s", loginResp.Token)), nil
+	return toolResult(
+		fmt.Sprintf("Logged in to %s as user %d %s", creds.Backend.Name, creds.User.ID, creds.User.Email),
+		map[string]interface{}{"logged_in": true, "backend": creds.Backend.Name, "user": creds.User},
+	)
 }
 
-// สร้าง Tool สำหรับดึงจำนวนผู้เข้าชม
-func CreateVisitorCountTool() mcp.Tool {
+// สร้าง Tool สำหรับดึงจำนวนผู้เข้าชม โดย backends คือชื่อ backend ที่เลือกได้จาก IGateway.Backends
+func CreateVisitorCountTool(backends []string) mcp.Tool {
 	return mcp.NewTool("get_visitor_count",
 		mcp.WithDescription("Get the current visitor count"),
-		mcp.WithString("base_url",
-			mcp.Required(),
-			mcp.Description("Base URL of the API (e.g., http://localhost:8001)"),
-		),
+		readOnlyTool(),
+		withBackendArgument(backends),
 	)
 }
 
 // VisitorCountHandler เป็นฟังก์ชันสำหรับดึงจำนวนผู้เข้าชม
-func VisitorCountHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	baseURL, ok := request.Params.Arguments["base_url"].(string)
-	if !ok {
-		return nil, errors.New("base_url must be a string")
-	}
+func (s *Server) VisitorCountHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	backend := request.GetString("backend", "")
 
-	// ตัดเครื่องหมาย / ถ้ามีที่ท้าย baseURL
-	baseURL = strings.TrimSuffix(baseURL, "/")
-
-	// เรียก API เพื่อดึงจำนวนผู้เข้าชม
-	visitorURL := fmt.Sprintf("%s/api/visitors", baseURL)
-	resp, err := http.Get(visitorURL)
-	if err != nil {
-		return nil, fmt.Errorf("visitor count request failed: %v", err)
+	// ถ้าไม่ได้ระบุ backend และ session ล็อกอินอยู่ ให้ใช้ backend ของ session
+	if backend == "" {
+		if creds, err := s.credentialsFromContext(ctx); err == nil {
+			backend = creds.Backend.Name
+		}
 	}
-	defer resp.Body.Close()
 
-	// อ่าน response
-	body, err := io.ReadAll(resp.Body)
+	count, err := s.gateway.GetVisitorCount(ctx, backend)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("visitor count request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// แปลง response เป็น struct
-	var visitorResp VisitorResponse
-	if err := json.Unmarshal(body, &visitorResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
 	}
 
-	// ส่งคืนผลลัพธ์เป็น text
-	return mcp.NewToolResultText(fmt.Sprintf("Visitor count: %d", visitorResp.Count)), nil
+	return toolResult(fmt.Sprintf("Visitor count: %d", count), map[string]interface{}{"visitor_count": count})
 }
 
 // สร้าง Tool สำหรับดึงข้อมูลบันทึกตาม ID
 func CreateGetNoteTool() mcp.Tool {
 	return mcp.NewTool("get_note",
-		mcp.WithDescription("Get a note by ID"),
-		mcp.WithString("base_url",
-			mcp.Required(),
-			mcp.Description("Base URL of the API (e.g., http://localhost:8001)"),
-		),
-		mcp.WithString("token",
-			mcp.Required(),
-			mcp.Description("JWT token for authentication"),
-		),
+		mcp.WithDescription("Get a note by ID (requires login)"),
+		readOnlyTool(),
 		mcp.WithString("id",
 			mcp.Required(),
 			mcp.Description("ID of the note to retrieve"),
@@ -186,66 +147,21 @@ func CreateGetNoteTool() mcp.Tool {
 }
 
 // GetNoteHandler เป็นฟังก์ชันสำหรับดึงข้อมูลบันทึกตาม ID
-func GetNoteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	baseURL, ok := request.Params.Arguments["base_url"].(string)
-	if !ok {
-		return nil, errors.New("base_url must be a string")
-	}
-
-	token, ok := request.Params.Arguments["token"].(string)
-	if !ok {
-		return nil, errors.New("token must be a string")
-	}
-
-	id, ok := request.Params.Arguments["id"].(string)
-	if !ok {
-		return nil, errors.New("id must be a string")
-	}
-
-	// ตัดเครื่องหมาย / ถ้ามีที่ท้าย baseURL
-	baseURL = strings.TrimSuffix(baseURL, "/")
-
-	// สร้าง HTTP request เพื่อดึงข้อมูล note
-	noteURL := fmt.Sprintf("%s/api/notes/%s", baseURL, id)
-	req, err := http.NewRequest("GET", noteURL, nil)
+func (s *Server) GetNoteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	creds, err := s.credentialsFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	// เพิ่ม Authorization header
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
-
-	// ส่ง request
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-	resp, err := client.Do(req)
+	id, err := noteIDFromRequest(request)
 	if err != nil {
-		return nil, fmt.Errorf("note request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// อ่าน response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("note request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// แปลง response เป็น struct
-	var note Note
-	if err := json.Unmarshal(body, &note); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+		return nil, err
 	}
 
-	// ส่งคืนผลลัพธ์เป็น JSON
-	noteJSON, err := json.MarshalIndent(note, "", "  ")
+	note, err := s.gateway.GetNote(ctx, creds, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal note: %v", err)
+		return nil, err
 	}
 
-	return mcp.NewToolResultText(string(noteJSON)), nil
+	return toolResult(fmt.Sprintf("Note %d: %s\n\n%s", note.ID, note.Title, note.Content), note)
 }
@@ -0,0 +1,227 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Transport คือชนิดของช่องทางการสื่อสารระหว่าง MCP client กับ server
+type Transport string
+
+const (
+	// TransportStdio สื่อสารผ่าน stdin/stdout (ค่าเริ่มต้น เหมาะกับ client ที่ spawn process เอง)
+	TransportStdio Transport = "stdio"
+	// TransportSSE สื่อสารผ่าน HTTP Server-Sent Events
+	TransportSSE Transport = "sse"
+	// TransportStreamableHTTP สื่อสารผ่าน MCP Streamable HTTP
+	TransportStreamableHTTP Transport = "http"
+)
+
+// TransportConfig คือการตั้งค่าสำหรับการเปิดให้บริการ MCP server
+type TransportConfig struct {
+	// Transport คือช่องทางที่ใช้ (stdio, sse, http)
+	Transport Transport
+	// Addr คือ address ที่ใช้ listen สำหรับ sse และ http เช่น ":8090"
+	Addr string
+	// BasePath คือ path ที่ใช้ mount MCP endpoint เช่น "/mcp"
+	BasePath string
+	// BaseURL คือ URL ภายนอกที่ client ใช้เรียก (ใช้กับ sse เมื่ออยู่หลัง load balancer)
+	BaseURL string
+	// KeepAlive เปิดการส่ง ping/heartbeat เพื่อไม่ให้ connection ถูกตัดโดย proxy
+	KeepAlive bool
+	// KeepAliveInterval คือช่วงเวลาระหว่าง ping/heartbeat
+	KeepAliveInterval time.Duration
+	// ShutdownTimeout คือเวลาสูงสุดที่รอให้ connection ปิดตัวลงเมื่อได้รับสัญญาณหยุด
+	ShutdownTimeout time.Duration
+}
+
+// LoadTransportConfigFromEnv อ่านการตั้งค่า transport จาก environment variables
+// และคืน error เมื่อค่าของ MCP_KEEP_ALIVE, MCP_KEEP_ALIVE_INTERVAL หรือ MCP_SHUTDOWN_TIMEOUT ไม่ถูกต้อง
+func LoadTransportConfigFromEnv() (TransportConfig, error) {
+	cfg := TransportConfig{
+		Transport:         TransportStdio,
+		Addr:              ":8090",
+		BasePath:          "/mcp",
+		KeepAlive:         true,
+		KeepAliveInterval: 30 * time.Second,
+		ShutdownTimeout:   10 * time.Second,
+	}
+
+	if v := os.Getenv("MCP_TRANSPORT"); v != "" {
+		cfg.Transport = Transport(strings.ToLower(v))
+	}
+	if v := os.Getenv("MCP_LISTEN_ADDR"); v != "" {
+		cfg.Addr = v
+	}
+	if v := os.Getenv("MCP_BASE_PATH"); v != "" {
+		cfg.BasePath = v
+	}
+	cfg.BaseURL = os.Getenv("MCP_BASE_URL")
+	if v := os.Getenv("MCP_KEEP_ALIVE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid MCP_KEEP_ALIVE %q (expected true or false)", v)
+		}
+		cfg.KeepAlive = b
+	}
+	if v := os.Getenv("MCP_KEEP_ALIVE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid MCP_KEEP_ALIVE_INTERVAL %q (expected a positive duration such as 30s)", v)
+		}
+		cfg.KeepAliveInterval = d
+	}
+	if v := os.Getenv("MCP_SHUTDOWN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid MCP_SHUTDOWN_TIMEOUT %q (expected a positive duration such as 10s)", v)
+		}
+		cfg.ShutdownTimeout = d
+	}
+
+	return cfg, nil
+}
+
+// Validate ตรวจสอบความถูกต้องของการตั้งค่า
+func (c TransportConfig) Validate() error {
+	switch c.Transport {
+	case TransportStdio:
+		return nil
+	case TransportSSE, TransportStreamableHTTP:
+	default:
+		return fmt.Errorf("unknown transport %q (expected stdio, sse or http)", c.Transport)
+	}
+
+	if c.Addr == "" {
+		return errors.New("listen address is required for network transports")
+	}
+	if !strings.HasPrefix(c.BasePath, "/") {
+		return fmt.Errorf("base path %q must start with /", c.BasePath)
+	}
+	return nil
+}
+
+// Serve เปิดให้บริการ MCP server ตาม transport ที่กำหนด และจะหยุดทำงานอย่างนุ่มนวลเมื่อ ctx ถูกยกเลิก
+func Serve(ctx context.Context, s *Server, cfg TransportConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	switch cfg.Transport {
+	case TransportSSE:
+		return serveSSE(ctx, s, cfg)
+	case TransportStreamableHTTP:
+		return serveStreamableHTTP(ctx, s, cfg)
+	default:
+		return serveStdio(ctx, s)
+	}
+}
+
+// serveStdio ให้บริการผ่าน stdin/stdout โดย log จะออกทาง stderr เพื่อไม่ให้ปนกับ protocol
+func serveStdio(ctx context.Context, s *Server) error {
+	stdioServer := server.NewStdioServer(s.MCPServer)
+	stdioServer.SetErrorLogger(log.New(os.Stderr, "", log.LstdFlags))
+
+	stdout := &syncWriter{w: os.Stdout}
+	err := stdioServer.Listen(ctx, s.subscriptionReader(ctx, os.Stdin, stdout), stdout)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// serveSSE ให้บริการผ่าน SSE ที่ {BasePath}/sse และ {BasePath}/message
+func serveSSE(ctx context.Context, s *Server, cfg TransportConfig) error {
+	httpServer := &http.Server{Addr: cfg.Addr}
+
+	opts := []server.SSEOption{
+		server.WithStaticBasePath(cfg.BasePath),
+		server.WithHTTPServer(httpServer),
+		server.WithKeepAlive(cfg.KeepAlive),
+	}
+	if cfg.BaseURL != "" {
+		opts = append(opts, server.WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.KeepAlive {
+		opts = append(opts, server.WithKeepAliveInterval(cfg.KeepAliveInterval))
+	}
+
+	sseServer := server.NewSSEServer(s.MCPServer, opts...)
+	httpServer.Handler = newTransportMux(strings.TrimSuffix(cfg.BasePath, "/")+"/", s.sseSubscriptionHandler(sseServer))
+
+	log.Printf("MCP SSE server listening on %s (sse: %s, message: %s)",
+		cfg.Addr, sseServer.CompleteSsePath(), sseServer.CompleteMessagePath())
+
+	return runHTTPTransport(ctx, cfg, httpServer.ListenAndServe, sseServer.Shutdown)
+}
+
+// serveStreamableHTTP ให้บริการผ่าน Streamable HTTP ที่ {BasePath}
+func serveStreamableHTTP(ctx context.Context, s *Server, cfg TransportConfig) error {
+	httpServer := &http.Server{Addr: cfg.Addr}
+
+	opts := []server.StreamableHTTPOption{
+		server.WithEndpointPath(cfg.BasePath),
+		server.WithStreamableHTTPServer(httpServer),
+	}
+	if cfg.KeepAlive {
+		opts = append(opts, server.WithHeartbeatInterval(cfg.KeepAliveInterval))
+	}
+
+	httpTransport := server.NewStreamableHTTPServer(s.MCPServer, opts...)
+	httpServer.Handler = newTransportMux(cfg.BasePath, s.subscriptionHandler(httpTransport))
+
+	log.Printf("MCP streamable HTTP server listening on %s (endpoint: %s)", cfg.Addr, cfg.BasePath)
+
+	return runHTTPTransport(ctx, cfg, httpServer.ListenAndServe, httpTransport.Shutdown)
+}
+
+// newTransportMux สร้าง mux ที่มี MCP handler และ health check สำหรับ load balancer
+func newTransportMux(pattern string, handler http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handler)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	})
+	return mux
+}
+
+// runHTTPTransport เริ่ม HTTP server และเรียก shutdown เมื่อ ctx ถูกยกเลิก
+func runHTTPTransport(ctx context.Context, cfg TransportConfig, start func() error, shutdown func(context.Context) error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- start()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down MCP server (timeout %s)", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown MCP server: %w", err)
+	}
+
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package mcpserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadTransportConfigFromEnv(t *testing.T) {
+	cases := []struct {
+		name    string
+		env     map[string]string
+		want    TransportConfig
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: TransportConfig{Transport: TransportStdio, Addr: ":8090", BasePath: "/mcp", KeepAlive: true, KeepAliveInterval: 30 * time.Second, ShutdownTimeout: 10 * time.Second},
+		},
+		{
+			name: "overrides",
+			env:  map[string]string{"MCP_TRANSPORT": "HTTP", "MCP_KEEP_ALIVE": "false", "MCP_KEEP_ALIVE_INTERVAL": "1m", "MCP_SHUTDOWN_TIMEOUT": "5s"},
+			want: TransportConfig{Transport: TransportStreamableHTTP, Addr: ":8090", BasePath: "/mcp", KeepAlive: false, KeepAliveInterval: time.Minute, ShutdownTimeout: 5 * time.Second},
+		},
+		{
+			name:    "invalid keep alive",
+			env:     map[string]string{"MCP_KEEP_ALIVE": "sometimes"},
+			wantErr: `invalid MCP_KEEP_ALIVE "sometimes" (expected true or false)`,
+		},
+		{
+			name:    "invalid keep alive interval",
+			env:     map[string]string{"MCP_KEEP_ALIVE_INTERVAL": "30"},
+			wantErr: `invalid MCP_KEEP_ALIVE_INTERVAL "30" (expected a positive duration such as 30s)`,
+		},
+		{
+			name:    "negative shutdown timeout",
+			env:     map[string]string{"MCP_SHUTDOWN_TIMEOUT": "-5s"},
+			wantErr: `invalid MCP_SHUTDOWN_TIMEOUT "-5s" (expected a positive duration such as 10s)`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"MCP_TRANSPORT", "MCP_LISTEN_ADDR", "MCP_BASE_PATH", "MCP_BASE_URL", "MCP_KEEP_ALIVE", "MCP_KEEP_ALIVE_INTERVAL", "MCP_SHUTDOWN_TIMEOUT"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			cfg, err := LoadTransportConfigFromEnv()
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTransportConfigFromEnv failed: %v", err)
+			}
+			if cfg != tc.want {
+				t.Errorf("config = %+v, want %+v", cfg, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+type CreateAuditLogs_20261017090000 struct{}
+
+// Name returns the name of the migration
+func (m *CreateAuditLogs_20261017090000) Name() string {
+	return "20261017090000_create_audit_logs"
+}
+
+// Up is the function to upgrade database
+func (m *CreateAuditLogs_20261017090000) Up(tx *gorm.DB) error {
+	return tx.AutoMigrate(&models.AuditLog{})
+}
+
+// Down is the function to downgrade database
+func (m *CreateAuditLogs_20261017090000) Down(tx *gorm.DB) error {
+	return tx.Migrator().DropTable("audit_logs")
+}
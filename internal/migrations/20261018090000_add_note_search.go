@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type AddNoteSearch_20261018090000 struct{}
+
+// Name returns the name of the migration
+func (m *AddNoteSearch_20261018090000) Name() string {
+	return "20261018090000_add_note_search"
+}
+
+// Up is the function to upgrade database
+func (m *AddNoteSearch_20261018090000) Up(tx *gorm.DB) error {
+	statements := []string{
+		// Trigram indexes let ILIKE match Thai text, which has no spaces between words
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		// The simple configuration keeps words as written, so it works for both English and Thai tokens
+		`ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+			) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN (search_vector)`,
+		`CREATE INDEX IF NOT EXISTS idx_notes_title_trgm ON notes USING GIN (title gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_notes_content_trgm ON notes USING GIN (content gin_trgm_ops)`,
+	}
+
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down is the function to downgrade database
+func (m *AddNoteSearch_20261018090000) Down(tx *gorm.DB) error {
+	statements := []string{
+		`DROP INDEX IF EXISTS idx_notes_content_trgm`,
+		`DROP INDEX IF EXISTS idx_notes_title_trgm`,
+		`DROP INDEX IF EXISTS idx_notes_search_vector`,
+		`ALTER TABLE notes DROP COLUMN IF EXISTS search_vector`,
+	}
+
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
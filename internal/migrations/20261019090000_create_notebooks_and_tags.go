@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+type CreateNotebooksAndTags_20261019090000 struct{}
+
+// Name returns the name of the migration
+func (m *CreateNotebooksAndTags_20261019090000) Name() string {
+	return "20261019090000_create_notebooks_and_tags"
+}
+
+// Up is the function to upgrade database
+func (m *CreateNotebooksAndTags_20261019090000) Up(tx *gorm.DB) error {
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		// Create notebooks and tags tables
+		if err := tx.AutoMigrate(&models.Notebook{}, &models.Tag{}); err != nil {
+			return err
+		}
+
+		// Add notes.notebook_id and the note_tags join table
+		return tx.AutoMigrate(&models.Note{})
+	})
+}
+
+// Down is the function to downgrade database
+func (m *CreateNotebooksAndTags_20261019090000) Down(tx *gorm.DB) error {
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable("note_tags"); err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().DropColumn(&models.Note{}, "NotebookID"); err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().DropTable("tags"); err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropTable("notebooks")
+	})
+}
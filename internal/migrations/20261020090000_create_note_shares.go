@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+type CreateNoteShares_20261020090000 struct{}
+
+// Name returns the name of the migration
+func (m *CreateNoteShares_20261020090000) Name() string {
+	return "20261020090000_create_note_shares"
+}
+
+// Up is the function to upgrade database
+func (m *CreateNoteShares_20261020090000) Up(tx *gorm.DB) error {
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		// Create note_shares table
+		if err := tx.AutoMigrate(&models.NoteShare{}); err != nil {
+			return err
+		}
+
+		// Add notes.last_edited_by_id
+		return tx.AutoMigrate(&models.Note{})
+	})
+}
+
+// Down is the function to downgrade database
+func (m *CreateNoteShares_20261020090000) Down(tx *gorm.DB) error {
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&models.Note{}, "LastEditedByID"); err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropTable("note_shares")
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+type CreateNoteRevisions_20261021090000 struct{}
+
+// Name returns the name of the migration
+func (m *CreateNoteRevisions_20261021090000) Name() string {
+	return "20261021090000_create_note_revisions"
+}
+
+// Up is the function to upgrade database
+func (m *CreateNoteRevisions_20261021090000) Up(tx *gorm.DB) error {
+	// Run migration in transaction
+	return tx.Transaction(func(tx *gorm.DB) error {
+		// Create note_revisions table
+		if err := tx.AutoMigrate(&models.NoteRevision{}); err != nil {
+			return err
+		}
+
+		// Existing notes start their history with their current title and content as revision 1
+		return tx.Exec(`INSERT INTO note_revisions (note_id, revision, title, content, edited_by_id, created_at)
+			SELECT id, 1, title, content, COALESCE(last_edited_by_id, user_id), updated_at
+			FROM notes
+			WHERE NOT EXISTS (SELECT 1 FROM note_revisions WHERE note_revisions.note_id = notes.id)`).Error
+	})
+}
+
+// Down is the function to downgrade database
+func (m *CreateNoteRevisions_20261021090000) Down(tx *gorm.DB) error {
+	return tx.Migrator().DropTable("note_revisions")
+}
@@ -36,6 +36,11 @@ func NewRegistry() *Registry {
 	registry.Register(
 		&CreateInitialTables_20250413111742{},
 		&SeedInitialUsers_20250413111743{},
+		&CreateAuditLogs_20261017090000{},
+		&AddNoteSearch_20261018090000{},
+		&CreateNotebooksAndTags_20261019090000{},
+		&CreateNoteShares_20261020090000{},
+		&CreateNoteRevisions_20261021090000{},
 	)
 
 	return registry
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=./audit_log_repository.go -destination=./mocks/mock_audit_log_repository.go -package=mocks
+
+// IAuditLogRepository is an interface for storing audit logs in the database
+type IAuditLogRepository interface {
+	Create(log *models.AuditLog) error
+}
+
+// AuditLogRepository is a struct that implements IAuditLogRepository
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new instance of AuditLogRepository
+func NewAuditLogRepository(db *gorm.DB) IAuditLogRepository {
+	return &AuditLogRepository{
+		db: db,
+	}
+}
+
+// Create adds a new audit log to the database
+func (r *AuditLogRepository) Create(log *models.AuditLog) error {
+	return r.db.Create(log).Error
+}
@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./note_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	repository "github.com/Napat/mcpserver-demo/internal/repository"
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINoteRepository is a mock of INoteRepository interface.
+type MockINoteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockINoteRepositoryMockRecorder
+}
+
+// MockINoteRepositoryMockRecorder is the mock recorder for MockINoteRepository.
+type MockINoteRepositoryMockRecorder struct {
+	mock *MockINoteRepository
+}
+
+// NewMockINoteRepository creates a new mock instance.
+func NewMockINoteRepository(ctrl *gomock.Controller) *MockINoteRepository {
+	mock := &MockINoteRepository{ctrl: ctrl}
+	mock.recorder = &MockINoteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINoteRepository) EXPECT() *MockINoteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddTags mocks base method.
+func (m *MockINoteRepository) AddTags(noteID uint, tags []models.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTags", noteID, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTags indicates an expected call of AddTags.
+func (mr *MockINoteRepositoryMockRecorder) AddTags(noteID, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTags", reflect.TypeOf((*MockINoteRepository)(nil).AddTags), noteID, tags)
+}
+
+// Count mocks base method.
+func (m *MockINoteRepository) Count(userID uint, filter repository.NoteFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", userID, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockINoteRepositoryMockRecorder) Count(userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockINoteRepository)(nil).Count), userID, filter)
+}
+
+// Create mocks base method.
+func (m *MockINoteRepository) Create(note *models.Note) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockINoteRepositoryMockRecorder) Create(note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockINoteRepository)(nil).Create), note)
+}
+
+// Delete mocks base method.
+func (m *MockINoteRepository) Delete(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockINoteRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockINoteRepository)(nil).Delete), id)
+}
+
+// FindByID mocks base method.
+func (m *MockINoteRepository) FindByID(id uint) (*models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockINoteRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockINoteRepository)(nil).FindByID), id)
+}
+
+// FindByUserID mocks base method.
+func (m *MockINoteRepository) FindByUserID(userID uint) ([]models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockINoteRepositoryMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockINoteRepository)(nil).FindByUserID), userID)
+}
+
+// FindPage mocks base method.
+func (m *MockINoteRepository) FindPage(query repository.NoteListQuery) ([]models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", query)
+	ret0, _ := ret[0].([]models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockINoteRepositoryMockRecorder) FindPage(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockINoteRepository)(nil).FindPage), query)
+}
+
+// RemoveTag mocks base method.
+func (m *MockINoteRepository) RemoveTag(noteID, tagID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTag", noteID, tagID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTag indicates an expected call of RemoveTag.
+func (mr *MockINoteRepositoryMockRecorder) RemoveTag(noteID, tagID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTag", reflect.TypeOf((*MockINoteRepository)(nil).RemoveTag), noteID, tagID)
+}
+
+// Restore mocks base method.
+func (m *MockINoteRepository) Restore(note *models.Note, revision int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", note, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockINoteRepositoryMockRecorder) Restore(note, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockINoteRepository)(nil).Restore), note, revision)
+}
+
+// Search mocks base method.
+func (m *MockINoteRepository) Search(userID uint, terms []string, limit int) ([]repository.NoteSearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", userID, terms, limit)
+	ret0, _ := ret[0].([]repository.NoteSearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockINoteRepositoryMockRecorder) Search(userID, terms, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockINoteRepository)(nil).Search), userID, terms, limit)
+}
+
+// Update mocks base method.
+func (m *MockINoteRepository) Update(note *models.Note) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockINoteRepositoryMockRecorder) Update(note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockINoteRepository)(nil).Update), note)
+}
+
+// UpdateNotebook mocks base method.
+func (m *MockINoteRepository) UpdateNotebook(noteID uint, notebookID *uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotebook", noteID, notebookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotebook indicates an expected call of UpdateNotebook.
+func (mr *MockINoteRepositoryMockRecorder) UpdateNotebook(noteID, notebookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotebook", reflect.TypeOf((*MockINoteRepository)(nil).UpdateNotebook), noteID, notebookID)
+}
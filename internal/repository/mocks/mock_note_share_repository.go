@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./note_share_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINoteShareRepository is a mock of INoteShareRepository interface.
+type MockINoteShareRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockINoteShareRepositoryMockRecorder
+}
+
+// MockINoteShareRepositoryMockRecorder is the mock recorder for MockINoteShareRepository.
+type MockINoteShareRepositoryMockRecorder struct {
+	mock *MockINoteShareRepository
+}
+
+// NewMockINoteShareRepository creates a new mock instance.
+func NewMockINoteShareRepository(ctrl *gomock.Controller) *MockINoteShareRepository {
+	mock := &MockINoteShareRepository{ctrl: ctrl}
+	mock.recorder = &MockINoteShareRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINoteShareRepository) EXPECT() *MockINoteShareRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockINoteShareRepository) Delete(noteID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", noteID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockINoteShareRepositoryMockRecorder) Delete(noteID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockINoteShareRepository)(nil).Delete), noteID, userID)
+}
+
+// Find mocks base method.
+func (m *MockINoteShareRepository) Find(noteID, userID uint) (*models.NoteShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", noteID, userID)
+	ret0, _ := ret[0].(*models.NoteShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockINoteShareRepositoryMockRecorder) Find(noteID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockINoteShareRepository)(nil).Find), noteID, userID)
+}
+
+// FindByNoteID mocks base method.
+func (m *MockINoteShareRepository) FindByNoteID(noteID uint) ([]models.NoteShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNoteID", noteID)
+	ret0, _ := ret[0].([]models.NoteShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNoteID indicates an expected call of FindByNoteID.
+func (mr *MockINoteShareRepositoryMockRecorder) FindByNoteID(noteID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNoteID", reflect.TypeOf((*MockINoteShareRepository)(nil).FindByNoteID), noteID)
+}
+
+// FindByUserID mocks base method.
+func (m *MockINoteShareRepository) FindByUserID(userID uint) ([]models.NoteShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]models.NoteShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockINoteShareRepositoryMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockINoteShareRepository)(nil).FindByUserID), userID)
+}
+
+// Upsert mocks base method.
+func (m *MockINoteShareRepository) Upsert(share *models.NoteShare) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockINoteShareRepositoryMockRecorder) Upsert(share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockINoteShareRepository)(nil).Upsert), share)
+}
@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./notebook_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINotebookRepository is a mock of INotebookRepository interface.
+type MockINotebookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotebookRepositoryMockRecorder
+}
+
+// MockINotebookRepositoryMockRecorder is the mock recorder for MockINotebookRepository.
+type MockINotebookRepositoryMockRecorder struct {
+	mock *MockINotebookRepository
+}
+
+// NewMockINotebookRepository creates a new mock instance.
+func NewMockINotebookRepository(ctrl *gomock.Controller) *MockINotebookRepository {
+	mock := &MockINotebookRepository{ctrl: ctrl}
+	mock.recorder = &MockINotebookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotebookRepository) EXPECT() *MockINotebookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountChildren mocks base method.
+func (m *MockINotebookRepository) CountChildren(id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountChildren", id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountChildren indicates an expected call of CountChildren.
+func (mr *MockINotebookRepositoryMockRecorder) CountChildren(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountChildren", reflect.TypeOf((*MockINotebookRepository)(nil).CountChildren), id)
+}
+
+// Create mocks base method.
+func (m *MockINotebookRepository) Create(notebook *models.Notebook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", notebook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockINotebookRepositoryMockRecorder) Create(notebook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockINotebookRepository)(nil).Create), notebook)
+}
+
+// Delete mocks base method.
+func (m *MockINotebookRepository) Delete(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockINotebookRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockINotebookRepository)(nil).Delete), id)
+}
+
+// FindByID mocks base method.
+func (m *MockINotebookRepository) FindByID(id uint) (*models.Notebook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*models.Notebook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockINotebookRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockINotebookRepository)(nil).FindByID), id)
+}
+
+// FindByUserID mocks base method.
+func (m *MockINotebookRepository) FindByUserID(userID uint) ([]models.Notebook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]models.Notebook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockINotebookRepositoryMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockINotebookRepository)(nil).FindByUserID), userID)
+}
+
+// Update mocks base method.
+func (m *MockINotebookRepository) Update(notebook *models.Notebook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", notebook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockINotebookRepositoryMockRecorder) Update(notebook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockINotebookRepository)(nil).Update), notebook)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./tag_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockITagRepository is a mock of ITagRepository interface.
+type MockITagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITagRepositoryMockRecorder
+}
+
+// MockITagRepositoryMockRecorder is the mock recorder for MockITagRepository.
+type MockITagRepositoryMockRecorder struct {
+	mock *MockITagRepository
+}
+
+// NewMockITagRepository creates a new mock instance.
+func NewMockITagRepository(ctrl *gomock.Controller) *MockITagRepository {
+	mock := &MockITagRepository{ctrl: ctrl}
+	mock.recorder = &MockITagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITagRepository) EXPECT() *MockITagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockITagRepository) Delete(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockITagRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockITagRepository)(nil).Delete), id)
+}
+
+// FindByID mocks base method.
+func (m *MockITagRepository) FindByID(id uint) (*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockITagRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockITagRepository)(nil).FindByID), id)
+}
+
+// FindByUserID mocks base method.
+func (m *MockITagRepository) FindByUserID(userID uint) ([]models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].([]models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockITagRepositoryMockRecorder) FindByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockITagRepository)(nil).FindByUserID), userID)
+}
+
+// FindOrCreate mocks base method.
+func (m *MockITagRepository) FindOrCreate(userID uint, names []string) ([]models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreate", userID, names)
+	ret0, _ := ret[0].([]models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrCreate indicates an expected call of FindOrCreate.
+func (mr *MockITagRepositoryMockRecorder) FindOrCreate(userID, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreate", reflect.TypeOf((*MockITagRepository)(nil).FindOrCreate), userID, names)
+}
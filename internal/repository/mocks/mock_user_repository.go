@@ -0,0 +1,226 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	multipart "mime/multipart"
+	reflect "reflect"
+
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIUserRepository is a mock of IUserRepository interface.
+type MockIUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIUserRepositoryMockRecorder
+}
+
+// MockIUserRepositoryMockRecorder is the mock recorder for MockIUserRepository.
+type MockIUserRepositoryMockRecorder struct {
+	mock *MockIUserRepository
+}
+
+// NewMockIUserRepository creates a new mock instance.
+func NewMockIUserRepository(ctrl *gomock.Controller) *MockIUserRepository {
+	mock := &MockIUserRepository{ctrl: ctrl}
+	mock.recorder = &MockIUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIUserRepository) EXPECT() *MockIUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountActiveWithRole mocks base method.
+func (m *MockIUserRepository) CountActiveWithRole(role models.UserRole) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveWithRole", role)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveWithRole indicates an expected call of CountActiveWithRole.
+func (mr *MockIUserRepositoryMockRecorder) CountActiveWithRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveWithRole", reflect.TypeOf((*MockIUserRepository)(nil).CountActiveWithRole), role)
+}
+
+// Create mocks base method.
+func (m *MockIUserRepository) Create(user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIUserRepositoryMockRecorder) Create(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIUserRepository)(nil).Create), user)
+}
+
+// Delete mocks base method.
+func (m *MockIUserRepository) Delete(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIUserRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIUserRepository)(nil).Delete), id)
+}
+
+// DeleteProfileImage mocks base method.
+func (m *MockIUserRepository) DeleteProfileImage(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfileImage", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfileImage indicates an expected call of DeleteProfileImage.
+func (mr *MockIUserRepositoryMockRecorder) DeleteProfileImage(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfileImage", reflect.TypeOf((*MockIUserRepository)(nil).DeleteProfileImage), userID)
+}
+
+// FindAll mocks base method.
+func (m *MockIUserRepository) FindAll() ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockIUserRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockIUserRepository)(nil).FindAll))
+}
+
+// FindByEmail mocks base method.
+func (m *MockIUserRepository) FindByEmail(email string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", email)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *MockIUserRepositoryMockRecorder) FindByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockIUserRepository)(nil).FindByEmail), email)
+}
+
+// FindByID mocks base method.
+func (m *MockIUserRepository) FindByID(id uint) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockIUserRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockIUserRepository)(nil).FindByID), id)
+}
+
+// GetLoginHistory mocks base method.
+func (m *MockIUserRepository) GetLoginHistory(userID uint, limit int) ([]models.LoginHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginHistory", userID, limit)
+	ret0, _ := ret[0].([]models.LoginHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginHistory indicates an expected call of GetLoginHistory.
+func (mr *MockIUserRepositoryMockRecorder) GetLoginHistory(userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginHistory", reflect.TypeOf((*MockIUserRepository)(nil).GetLoginHistory), userID, limit)
+}
+
+// RecordLogin mocks base method.
+func (m *MockIUserRepository) RecordLogin(history *models.LoginHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLogin", history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLogin indicates an expected call of RecordLogin.
+func (mr *MockIUserRepositoryMockRecorder) RecordLogin(history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLogin", reflect.TypeOf((*MockIUserRepository)(nil).RecordLogin), history)
+}
+
+// Update mocks base method.
+func (m *MockIUserRepository) Update(user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIUserRepositoryMockRecorder) Update(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIUserRepository)(nil).Update), user)
+}
+
+// UpdateActive mocks base method.
+func (m *MockIUserRepository) UpdateActive(id uint, active bool) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActive", id, active)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateActive indicates an expected call of UpdateActive.
+func (mr *MockIUserRepositoryMockRecorder) UpdateActive(id, active interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActive", reflect.TypeOf((*MockIUserRepository)(nil).UpdateActive), id, active)
+}
+
+// UpdateProfileImage mocks base method.
+func (m *MockIUserRepository) UpdateProfileImage(userID uint, file *multipart.FileHeader) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfileImage", userID, file)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfileImage indicates an expected call of UpdateProfileImage.
+func (mr *MockIUserRepositoryMockRecorder) UpdateProfileImage(userID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfileImage", reflect.TypeOf((*MockIUserRepository)(nil).UpdateProfileImage), userID, file)
+}
+
+// UpdateRole mocks base method.
+func (m *MockIUserRepository) UpdateRole(id uint, role models.UserRole) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", id, role)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockIUserRepositoryMockRecorder) UpdateRole(id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockIUserRepository)(nil).UpdateRole), id, role)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/Napat/mcpserver-demo/pkg/cache"
+)
+
+const (
+	// noteEventsChannel คือ channel ของ Redis pub/sub ที่ใช้กระจายการเปลี่ยนแปลงของบันทึก
+	noteEventsChannel = "notes:events"
+)
+
+// INoteEventRepository คือ interface สำหรับส่งและรับเหตุการณ์การเปลี่ยนแปลงของบันทึก
+type INoteEventRepository interface {
+	Publish(ctx context.Context, event models.NoteEvent) error
+	Subscribe(ctx context.Context) (<-chan models.NoteEvent, error)
+}
+
+// NoteEventRepository ทำหน้าที่กระจายเหตุการณ์ของบันทึกผ่าน Redis pub/sub
+type NoteEventRepository struct {
+	redisClient *cache.RedisClient
+}
+
+// NewNoteEventRepository สร้าง instance ใหม่ของ NoteEventRepository
+func NewNoteEventRepository(redisClient *cache.RedisClient) *NoteEventRepository {
+	return &NoteEventRepository{
+		redisClient: redisClient,
+	}
+}
+
+// Publish ส่งเหตุการณ์ไปยังผู้รับทุกรายที่ subscribe อยู่
+func (r *NoteEventRepository) Publish(ctx context.Context, event models.NoteEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.Publish(ctx, noteEventsChannel, payload)
+}
+
+// Subscribe รับเหตุการณ์ของบันทึกจนกว่า ctx จะถูกยกเลิก แล้วจึงปิด channel ที่คืนไป
+func (r *NoteEventRepository) Subscribe(ctx context.Context) (<-chan models.NoteEvent, error) {
+	pubsub := r.redisClient.Subscribe(ctx, noteEventsChannel)
+
+	// รอการยืนยันการ subscribe เพื่อให้รู้ทันทีหากเชื่อมต่อ Redis ไม่ได้
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan models.NoteEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event models.NoteEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					log.Printf("Ignoring malformed note event: %v", err)
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Napat/mcpserver-demo/models"
 	"gorm.io/gorm"
@@ -14,10 +17,100 @@ type INoteRepository interface {
 	Create(note *models.Note) error
 	FindByID(id uint) (*models.Note, error)
 	FindByUserID(userID uint) ([]models.Note, error)
+	FindPage(query NoteListQuery) ([]models.Note, error)
+	Count(userID uint, filter NoteFilter) (int64, error)
+	Search(userID uint, terms []string, limit int) ([]NoteSearchHit, error)
+	AddTags(noteID uint, tags []models.Tag) error
+	RemoveTag(noteID, tagID uint) error
+	UpdateNotebook(noteID uint, notebookID *uint) error
 	Update(note *models.Note) error
+	Restore(note *models.Note, revision int) error
 	Delete(id uint) error
 }
 
+// NoteSortField is a column that notes can be sorted by
+type NoteSortField string
+
+const (
+	// NoteSortCreatedAt sorts notes by creation time
+	NoteSortCreatedAt NoteSortField = "created_at"
+	// NoteSortUpdatedAt sorts notes by last update time
+	NoteSortUpdatedAt NoteSortField = "updated_at"
+	// NoteSortTitle sorts notes by title
+	NoteSortTitle NoteSortField = "title"
+)
+
+// NoteFilter narrows down the notes of a user
+// After bounds are inclusive and Before bounds are exclusive
+type NoteFilter struct {
+	TitleContains string
+	// TitlePrefix keeps the notes whose title starts with this text, ignoring case
+	TitlePrefix   string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	// Tags keeps the notes that have every one of these tag names
+	Tags []string
+	// NotebookID keeps the notes filed directly in this notebook
+	NotebookID *uint
+	// WithoutNotebook keeps the notes that are not filed in any notebook
+	WithoutNotebook bool
+}
+
+// NoteKey is the position of a note in a sorted listing: the sort column plus the ID as a tie-breaker
+type NoteKey struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Title     string    `json:"title,omitempty"`
+}
+
+// NoteKeyOf returns the position of a note when sorted by field
+func NoteKeyOf(note *models.Note, field NoteSortField) NoteKey {
+	key := NoteKey{ID: note.ID}
+	switch field {
+	case NoteSortUpdatedAt:
+		key.UpdatedAt = note.UpdatedAt
+	case NoteSortTitle:
+		key.Title = note.Title
+	default:
+		key.CreatedAt = note.CreatedAt
+	}
+	return key
+}
+
+// value returns the sort column value of the key
+func (k NoteKey) value(field NoteSortField) interface{} {
+	switch field {
+	case NoteSortUpdatedAt:
+		return k.UpdatedAt
+	case NoteSortTitle:
+		return k.Title
+	default:
+		return k.CreatedAt
+	}
+}
+
+// NoteListQuery selects one page of a user's notes using keyset pagination
+type NoteListQuery struct {
+	UserID     uint
+	Filter     NoteFilter
+	SortBy     NoteSortField
+	Descending bool
+	Limit      int
+	// After selects the notes that come after this key in the sort order
+	After *NoteKey
+	// Before selects the notes that come just before this key; they are still returned in the sort order
+	Before *NoteKey
+}
+
+// NoteSearchHit is a note found by Search together with its relevance
+type NoteSearchHit struct {
+	models.Note
+	Rank float64
+}
+
 // NoteRepository is a struct that implements INoteRepository
 type NoteRepository struct {
 	db *gorm.DB
@@ -30,15 +123,20 @@ func NewNoteRepository(db *gorm.DB) INoteRepository {
 	}
 }
 
-// Create adds a new note to the database
+// Create adds a new note to the database together with its first revision
 func (r *NoteRepository) Create(note *models.Note) error {
-	return r.db.Create(note).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+		return createRevision(tx, note, &note.UserID, nil)
+	})
 }
 
 // FindByID finds a note by ID
 func (r *NoteRepository) FindByID(id uint) (*models.Note, error) {
 	var note models.Note
-	result := r.db.First(&note, id)
+	result := r.db.Preload("Tags").First(&note, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("note not found")
@@ -51,7 +149,8 @@ func (r *NoteRepository) FindByID(id uint) (*models.Note, error) {
 // FindByUserID finds all notes for a user
 func (r *NoteRepository) FindByUserID(userID uint) ([]models.Note, error) {
 	var notes []models.Note
-	result := r.db.Where("user_id = ?", userID).
+	result := r.db.Preload("Tags").
+		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&notes)
 
@@ -61,11 +160,231 @@ func (r *NoteRepository) FindByUserID(userID uint) ([]models.Note, error) {
 	return notes, nil
 }
 
-// Update updates a note
+// FindPage finds a page of a user's notes ordered by query.SortBy and then by ID
+func (r *NoteRepository) FindPage(query NoteListQuery) ([]models.Note, error) {
+	field := query.SortBy
+	switch field {
+	case NoteSortCreatedAt, NoteSortUpdatedAt, NoteSortTitle:
+	default:
+		return nil, fmt.Errorf("invalid sort field: %s", field)
+	}
+
+	// Paging backwards reads the rows nearest to the key first, then restores the sort order below
+	key, backward := query.After, false
+	if query.Before != nil {
+		key, backward = query.Before, true
+	}
+	descending := query.Descending != backward
+
+	direction, operator := "ASC", ">"
+	if descending {
+		direction, operator = "DESC", "<"
+	}
+
+	db := r.filtered(query.UserID, query.Filter)
+	if key != nil {
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", field, operator), key.value(field), key.ID)
+	}
+
+	notes := []models.Note{}
+	result := db.Preload("Tags").
+		Order(fmt.Sprintf("%s %s, id %s", field, direction, direction)).
+		Limit(query.Limit).
+		Find(&notes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if backward {
+		for i, j := 0, len(notes)-1; i < j; i, j = i+1, j-1 {
+			notes[i], notes[j] = notes[j], notes[i]
+		}
+	}
+	return notes, nil
+}
+
+// Count counts a user's notes that match the filter
+func (r *NoteRepository) Count(userID uint, filter NoteFilter) (int64, error) {
+	var count int64
+	if err := r.filtered(userID, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Search finds a user's notes that match all terms, most relevant first
+// A note matches through the search_vector full-text index, or when every term appears in its title
+// or content; the trigram indexes keep that fallback fast and it covers Thai text without word spaces
+func (r *NoteRepository) Search(userID uint, terms []string, limit int) ([]NoteSearchHit, error) {
+	if len(terms) == 0 {
+		return []NoteSearchHit{}, nil
+	}
+
+	text := strings.Join(terms, " ")
+	tsQuery := "plainto_tsquery('simple', ?)"
+
+	db := r.db.Model(&models.Note{}).Where("user_id = ?", userID)
+
+	conditions := make([]string, 0, len(terms))
+	args := []interface{}{text}
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		conditions = append(conditions, "(title ILIKE ? ESCAPE '\\' OR content ILIKE ? ESCAPE '\\')")
+		args = append(args, pattern, pattern)
+	}
+	db = db.Where("search_vector @@ "+tsQuery+" OR ("+strings.Join(conditions, " AND ")+")", args...)
+
+	// Full-text rank favours title matches (weight A); trigram similarity ranks the substring-only matches
+	rank := "ts_rank_cd(search_vector, " + tsQuery + ") + word_similarity(?, title) + 0.5 * word_similarity(?, content)"
+
+	hits := []NoteSearchHit{}
+	result := db.Select("notes.*, "+rank+" AS rank", text, text, text).
+		Order("rank DESC, updated_at DESC, id DESC").
+		Limit(limit).
+		Scan(&hits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := r.loadTags(hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// loadTags fills in the tags of search hits, which Scan does not preload
+func (r *NoteRepository) loadTags(hits []NoteSearchHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	var rows []struct {
+		NoteID uint
+		models.Tag
+	}
+	err := r.db.Table("tags").
+		Select("note_tags.note_id, tags.*").
+		Joins("JOIN note_tags ON note_tags.tag_id = tags.id").
+		Where("note_tags.note_id IN ?", ids).
+		Order("tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byNote := make(map[uint][]models.Tag, len(hits))
+	for _, row := range rows {
+		byNote[row.NoteID] = append(byNote[row.NoteID], row.Tag)
+	}
+	for i := range hits {
+		hits[i].Tags = byNote[hits[i].ID]
+	}
+	return nil
+}
+
+// AddTags attaches tags to a note; tags the note already has are kept once
+func (r *NoteRepository) AddTags(noteID uint, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Note{ID: noteID}).Association("Tags").Append(tags)
+}
+
+// RemoveTag detaches a tag from a note
+func (r *NoteRepository) RemoveTag(noteID, tagID uint) error {
+	return r.db.Model(&models.Note{ID: noteID}).Association("Tags").Delete(&models.Tag{ID: tagID})
+}
+
+// UpdateNotebook files a note in a notebook, or takes it out of its notebook when notebookID is nil
+func (r *NoteRepository) UpdateNotebook(noteID uint, notebookID *uint) error {
+	return r.db.Model(&models.Note{ID: noteID}).Update("notebook_id", notebookID).Error
+}
+
+// filtered builds a query for a user's notes that match the filter
+func (r *NoteRepository) filtered(userID uint, filter NoteFilter) *gorm.DB {
+	db := r.db.Model(&models.Note{}).Where("user_id = ?", userID)
+
+	if filter.TitleContains != "" {
+		db = db.Where("title ILIKE ? ESCAPE '\\'", "%"+escapeLike(filter.TitleContains)+"%")
+	}
+	if filter.TitlePrefix != "" {
+		db = db.Where("title ILIKE ? ESCAPE '\\'", escapeLike(filter.TitlePrefix)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedAfter != nil {
+		db = db.Where("updated_at >= ?", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		db = db.Where("updated_at < ?", *filter.UpdatedBefore)
+	}
+	if filter.NotebookID != nil {
+		db = db.Where("notebook_id = ?", *filter.NotebookID)
+	}
+	if filter.WithoutNotebook {
+		db = db.Where("notebook_id IS NULL")
+	}
+	for _, tag := range filter.Tags {
+		db = db.Where("EXISTS (SELECT 1 FROM note_tags JOIN tags ON tags.id = note_tags.tag_id WHERE note_tags.note_id = notes.id AND tags.name = ?)", tag)
+	}
+	return db
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
+
+// Update updates a note and records the new title and content as a revision
 func (r *NoteRepository) Update(note *models.Note) error {
-	return r.db.Model(note).
-		Select("title", "content", "updated_at").
-		Updates(note).Error
+	return r.update(note, nil)
+}
+
+// Restore updates a note with the title and content of an old revision and records them as a new revision
+func (r *NoteRepository) Restore(note *models.Note, revision int) error {
+	return r.update(note, &revision)
+}
+
+// update writes the note and its new revision in one transaction
+func (r *NoteRepository) update(note *models.Note, restoredFrom *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Updating the row locks it until commit, so concurrent updates get consecutive revision numbers
+		if err := tx.Model(note).
+			Select("title", "content", "last_edited_by_id", "updated_at").
+			Updates(note).Error; err != nil {
+			return err
+		}
+		return createRevision(tx, note, note.LastEditedByID, restoredFrom)
+	})
+}
+
+// createRevision stores the current title and content of a note as its next revision
+func createRevision(tx *gorm.DB, note *models.Note, editedByID *uint, restoredFrom *int) error {
+	var latest int
+	if err := tx.Model(&models.NoteRevision{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Where("note_id = ?", note.ID).
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.NoteRevision{
+		NoteID:       note.ID,
+		Revision:     latest + 1,
+		Title:        note.Title,
+		Content:      note.Content,
+		EditedByID:   editedByID,
+		RestoredFrom: restoredFrom,
+	}).Error
 }
 
 // Delete removes a note
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=./note_revision_repository.go -destination=./mocks/mock_note_revision_repository.go -package=mocks
+
+// INoteRevisionRepository is an interface for reading note revisions from the database
+// Revisions are written by INoteRepository and are never changed afterwards
+type INoteRevisionRepository interface {
+	FindByNoteID(noteID uint) ([]models.NoteRevision, error)
+	FindByRevision(noteID uint, revision int) (*models.NoteRevision, error)
+	FindLatest(noteID uint) (*models.NoteRevision, error)
+}
+
+// NoteRevisionRepository is a struct that implements INoteRevisionRepository
+type NoteRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteRevisionRepository creates a new instance of NoteRevisionRepository
+func NewNoteRevisionRepository(db *gorm.DB) INoteRevisionRepository {
+	return &NoteRevisionRepository{
+		db: db,
+	}
+}
+
+// FindByNoteID finds every revision of a note, newest first
+func (r *NoteRevisionRepository) FindByNoteID(noteID uint) ([]models.NoteRevision, error) {
+	revisions := []models.NoteRevision{}
+	result := r.db.Where("note_id = ?", noteID).
+		Order("revision DESC").
+		Find(&revisions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+// FindByRevision finds one revision of a note by its revision number
+func (r *NoteRevisionRepository) FindByRevision(noteID uint, revision int) (*models.NoteRevision, error) {
+	return r.first(r.db.Where("note_id = ? AND revision = ?", noteID, revision))
+}
+
+// FindLatest finds the newest revision of a note
+func (r *NoteRevisionRepository) FindLatest(noteID uint) (*models.NoteRevision, error) {
+	return r.first(r.db.Where("note_id = ?", noteID).Order("revision DESC"))
+}
+
+// first returns the first revision matched by query
+func (r *NoteRevisionRepository) first(query *gorm.DB) (*models.NoteRevision, error) {
+	var revision models.NoteRevision
+	result := query.First(&revision)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, result.Error
+	}
+	return &revision, nil
+}
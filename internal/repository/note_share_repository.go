@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=./note_share_repository.go -destination=./mocks/mock_note_share_repository.go -package=mocks
+
+// INoteShareRepository is an interface for managing note shares in the database
+type INoteShareRepository interface {
+	Upsert(share *models.NoteShare) error
+	Find(noteID, userID uint) (*models.NoteShare, error)
+	FindByNoteID(noteID uint) ([]models.NoteShare, error)
+	FindByUserID(userID uint) ([]models.NoteShare, error)
+	Delete(noteID, userID uint) error
+}
+
+// NoteShareRepository is a struct that implements INoteShareRepository
+type NoteShareRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteShareRepository creates a new instance of NoteShareRepository
+func NewNoteShareRepository(db *gorm.DB) INoteShareRepository {
+	return &NoteShareRepository{
+		db: db,
+	}
+}
+
+// Upsert shares a note with a user, or changes the permission when it is already shared with them
+func (r *NoteShareRepository) Upsert(share *models.NoteShare) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "note_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permission", "updated_at"}),
+	}).Create(share).Error
+}
+
+// Find finds the share of a note with a user
+func (r *NoteShareRepository) Find(noteID, userID uint) (*models.NoteShare, error) {
+	var share models.NoteShare
+	result := r.db.Where("note_id = ? AND user_id = ?", noteID, userID).First(&share)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("share not found")
+		}
+		return nil, result.Error
+	}
+	return &share, nil
+}
+
+// FindByNoteID finds everyone a note is shared with, including their user record
+func (r *NoteShareRepository) FindByNoteID(noteID uint) ([]models.NoteShare, error) {
+	shares := []models.NoteShare{}
+	result := r.db.Preload("User").
+		Where("note_id = ?", noteID).
+		Order("created_at ASC").
+		Find(&shares)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return shares, nil
+}
+
+// FindByUserID finds the notes shared with a user, including each note with its tags and owner
+func (r *NoteShareRepository) FindByUserID(userID uint) ([]models.NoteShare, error) {
+	shares := []models.NoteShare{}
+	result := r.db.Preload("Note.User").
+		Preload("Note.Tags").
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Find(&shares)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return shares, nil
+}
+
+// Delete removes the share of a note with a user
+func (r *NoteShareRepository) Delete(noteID, userID uint) error {
+	return r.db.Where("note_id = ? AND user_id = ?", noteID, userID).Delete(&models.NoteShare{}).Error
+}
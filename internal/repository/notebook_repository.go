@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=./notebook_repository.go -destination=./mocks/mock_notebook_repository.go -package=mocks
+
+// INotebookRepository is an interface for managing notebook data in the database
+type INotebookRepository interface {
+	Create(notebook *models.Notebook) error
+	FindByID(id uint) (*models.Notebook, error)
+	FindByUserID(userID uint) ([]models.Notebook, error)
+	Update(notebook *models.Notebook) error
+	Delete(id uint) error
+	CountChildren(id uint) (int64, error)
+}
+
+// NotebookRepository is a struct that implements INotebookRepository
+type NotebookRepository struct {
+	db *gorm.DB
+}
+
+// NewNotebookRepository creates a new instance of NotebookRepository
+func NewNotebookRepository(db *gorm.DB) INotebookRepository {
+	return &NotebookRepository{
+		db: db,
+	}
+}
+
+// Create adds a new notebook to the database
+func (r *NotebookRepository) Create(notebook *models.Notebook) error {
+	return r.db.Create(notebook).Error
+}
+
+// FindByID finds a notebook by ID
+func (r *NotebookRepository) FindByID(id uint) (*models.Notebook, error) {
+	var notebook models.Notebook
+	result := r.db.First(&notebook, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notebook not found")
+		}
+		return nil, result.Error
+	}
+	return &notebook, nil
+}
+
+// FindByUserID finds all notebooks of a user ordered by name
+func (r *NotebookRepository) FindByUserID(userID uint) ([]models.Notebook, error) {
+	notebooks := []models.Notebook{}
+	result := r.db.Where("user_id = ?", userID).
+		Order("name ASC, id ASC").
+		Find(&notebooks)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return notebooks, nil
+}
+
+// Update renames or moves a notebook
+func (r *NotebookRepository) Update(notebook *models.Notebook) error {
+	return r.db.Model(notebook).
+		Select("name", "parent_id", "updated_at").
+		Updates(notebook).Error
+}
+
+// Delete removes a notebook; its notes are no longer filed in any notebook
+func (r *NotebookRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Notebook{}, id).Error
+}
+
+// CountChildren counts the notebooks directly inside a notebook
+func (r *NotebookRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Notebook{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=./tag_repository.go -destination=./mocks/mock_tag_repository.go -package=mocks
+
+// ITagRepository is an interface for managing tag data in the database
+type ITagRepository interface {
+	FindByID(id uint) (*models.Tag, error)
+	FindByUserID(userID uint) ([]models.Tag, error)
+	FindOrCreate(userID uint, names []string) ([]models.Tag, error)
+	Delete(id uint) error
+}
+
+// TagRepository is a struct that implements ITagRepository
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new instance of TagRepository
+func NewTagRepository(db *gorm.DB) ITagRepository {
+	return &TagRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a tag by ID
+func (r *TagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.First(&tag, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// FindByUserID finds all tags of a user ordered by name
+func (r *TagRepository) FindByUserID(userID uint) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	result := r.db.Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&tags)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// FindOrCreate returns the user's tags with the given names, creating the ones that do not exist yet
+func (r *TagRepository) FindOrCreate(userID uint, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, models.Tag{UserID: userID, Name: name})
+	}
+
+	found := []models.Tag{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Another request may create the same tag at the same time, so existing names are skipped
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND name IN ?", userID, names).
+			Order("name ASC").
+			Find(&found).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// Delete removes a tag from every note and deletes it
+func (r *TagRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Tag{}, id).Error
+}
@@ -24,6 +24,12 @@ type IUserRepository interface {
 	GetLoginHistory(userID uint, limit int) ([]models.LoginHistory, error)
 	RecordLogin(history *models.LoginHistory) error
 
+	// Admin operations (include inactive users)
+	FindAll() ([]models.User, error)
+	UpdateActive(id uint, active bool) (*models.User, error)
+	UpdateRole(id uint, role models.UserRole) (*models.User, error)
+	CountActiveWithRole(role models.UserRole) (int64, error)
+
 	// File storage operations combined with database
 	UpdateProfileImage(userID uint, file *multipart.FileHeader) (string, error)
 	DeleteProfileImage(userID uint) error
@@ -201,6 +207,53 @@ func (r *UserRepository) RecordLogin(history *models.LoginHistory) error {
 	return r.db.Create(history).Error
 }
 
+// FindAll returns all users ordered by ID, including inactive ones
+func (r *UserRepository) FindAll() ([]models.User, error) {
+	var users []models.User
+	// Skip the AfterFind hook, which rejects inactive users
+	result := r.db.Session(&gorm.Session{SkipHooks: true}).Order("id ASC").Find(&users)
+	return users, result.Error
+}
+
+// UpdateActive activates or deactivates a user and returns the updated user
+func (r *UserRepository) UpdateActive(id uint, active bool) (*models.User, error) {
+	return r.updateColumn(id, "active", active)
+}
+
+// UpdateRole replaces the roles of a user and returns the updated user
+func (r *UserRepository) UpdateRole(id uint, role models.UserRole) (*models.User, error) {
+	return r.updateColumn(id, "role", role)
+}
+
+// CountActiveWithRole counts the active users that have the given role among their roles
+func (r *UserRepository) CountActiveWithRole(role models.UserRole) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.User{}).Where("active = ? AND role & ? <> 0", true, role).Count(&count)
+	return count, result.Error
+}
+
+// updateColumn updates a single column of a user, including inactive ones, and reloads the user
+func (r *UserRepository) updateColumn(id uint, column string, value interface{}) (*models.User, error) {
+	db := r.db.Session(&gorm.Session{SkipHooks: true})
+
+	result := db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		column:       value,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	var user models.User
+	if err := db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // extractObjectNameFromURL extracts object name from URL
 // Example: http://localhost:9000/profiles/avatar.jpg -> avatar.jpg
 func extractObjectNameFromURL(url string) string {
@@ -14,8 +14,25 @@ import (
 	"gorm.io/gorm"
 )
 
-// SetupRoutes ตั้งค่าเส้นทาง API
-func SetupRoutes(e *echo.Echo, db *gorm.DB, logger *zap.Logger) {
+// Services รวม services ของระบบ เพื่อใช้ร่วมกันระหว่าง REST API และ MCP server แบบ in-process
+type Services struct {
+	UserService    service.IUserService
+	NoteService    service.INoteService
+	VisitorService service.IVisitorService
+	// NotebookService จัดการสมุดบันทึกที่ใช้จัดกลุ่มบันทึก
+	NotebookService service.INotebookService
+	// NoteShareService จัดการการแชร์บันทึกให้ผู้ใช้อื่น
+	NoteShareService service.INoteShareService
+	// NoteEvents คือแหล่งเหตุการณ์การเปลี่ยนแปลงของบันทึกที่ NoteService ส่งออกมา
+	NoteEvents repository.INoteEventRepository
+	// AuditLogs เก็บ audit log ของการเรียก MCP tool
+	AuditLogs repository.IAuditLogRepository
+	// Redis คือ client ที่ services ใช้ร่วมกัน เช่นสำหรับเก็บสถานะ rate limit ของ MCP session
+	Redis *cache.RedisClient
+}
+
+// NewServices สร้าง repositories และ services ทั้งหมดจาก database และ dependencies ภายนอก
+func NewServices(db *gorm.DB, logger *zap.Logger) *Services {
 	// สร้าง dependencies
 	fileStorage, err := storage.NewMinioStorage()
 	if err != nil {
@@ -31,18 +48,37 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, logger *zap.Logger) {
 	// สร้าง repositories ตาม Facade pattern (รวมการเข้าถึง database และ storage)
 	userRepo := repository.NewUserRepository(db, fileStorage)
 	noteRepo := repository.NewNoteRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	notebookRepo := repository.NewNotebookRepository(db)
+	shareRepo := repository.NewNoteShareRepository(db)
+	revisionRepo := repository.NewNoteRevisionRepository(db)
 	visitorRepo := repository.NewVisitorRepository(redisClient)
+	noteEventRepo := repository.NewNoteEventRepository(redisClient)
 
 	// สร้าง services
-	userService := service.NewUserService(userRepo, logger)
-	noteService := service.NewNoteService(noteRepo, logger)
-	visitorService := service.NewVisitorService(visitorRepo, logger)
+	return &Services{
+		UserService:      service.NewUserService(userRepo, logger),
+		NoteService:      service.NewNoteService(noteRepo, tagRepo, notebookRepo, shareRepo, revisionRepo, noteEventRepo, logger),
+		VisitorService:   service.NewVisitorService(visitorRepo, logger),
+		NotebookService:  service.NewNotebookService(notebookRepo, logger),
+		NoteShareService: service.NewNoteShareService(shareRepo, noteRepo, userRepo, logger),
+		NoteEvents:       noteEventRepo,
+		AuditLogs:        repository.NewAuditLogRepository(db),
+		Redis:            redisClient,
+	}
+}
 
+// SetupRoutes ตั้งค่าเส้นทาง API
+// mcpHandler คือ MCP server ที่พร้อมให้บริการแล้ว (ดู mcpserver.NewHTTPHandler) ซึ่งจะถูก mount ที่ /mcp หลังการตรวจ JWT
+func SetupRoutes(e *echo.Echo, services *Services, mcpHandler http.Handler, logger *zap.Logger) {
 	// สร้าง handlers
-	authHandler := handler.NewAuthHandler(userService, logger)
-	userHandler := handler.NewUserHandler(userService, logger)
-	noteHandler := handler.NewNoteHandler(noteService, logger)
-	visitorHandler := handler.NewVisitorHandler(visitorService, logger)
+	authHandler := handler.NewAuthHandler(services.UserService, logger)
+	userHandler := handler.NewUserHandler(services.UserService, logger)
+	noteHandler := handler.NewNoteHandler(services.NoteService, logger)
+	notebookHandler := handler.NewNotebookHandler(services.NotebookService, logger)
+	noteShareHandler := handler.NewNoteShareHandler(services.NoteShareService, logger)
+	visitorHandler := handler.NewVisitorHandler(services.VisitorService, logger)
+	adminHandler := handler.NewAdminHandler(services.UserService, logger)
 
 	// API Routes
 	api := e.Group("/api")
@@ -75,15 +111,50 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, logger *zap.Logger) {
 	notes := api.Group("/notes")
 	notes.Use(middleware.JWTMiddleware())
 	notes.GET("", noteHandler.GetAllNotes)
+	notes.GET("/search", noteHandler.SearchNotes)
+	notes.GET("/shared", noteShareHandler.GetSharedWithMe)
 	notes.GET("/:id", noteHandler.GetNote)
 	notes.POST("", noteHandler.CreateNote)
 	notes.PUT("/:id", noteHandler.UpdateNote)
 	notes.DELETE("/:id", noteHandler.DeleteNote)
+	notes.PUT("/:id/notebook", noteHandler.MoveNote)
+	notes.POST("/:id/tags", noteHandler.AddTags)
+	notes.DELETE("/:id/tags/:tagId", noteHandler.RemoveTag)
+	notes.GET("/:id/shares", noteShareHandler.GetShares)
+	notes.PUT("/:id/shares", noteShareHandler.ShareNote)
+	notes.DELETE("/:id/shares/:userId", noteShareHandler.UnshareNote)
+	notes.GET("/:id/revisions", noteHandler.GetRevisions)
+	notes.GET("/:id/revisions/diff", noteHandler.DiffRevisions)
+	notes.GET("/:id/revisions/:revision", noteHandler.GetRevision)
+	notes.POST("/:id/revisions/:revision/restore", noteHandler.RestoreRevision)
+
+	// Notebook and Tag Routes (Protected)
+	notebooks := api.Group("/notebooks")
+	notebooks.Use(middleware.JWTMiddleware())
+	notebooks.GET("", notebookHandler.GetAllNotebooks)
+	notebooks.POST("", notebookHandler.CreateNotebook)
+	notebooks.PUT("/:id", notebookHandler.UpdateNotebook)
+	notebooks.DELETE("/:id", notebookHandler.DeleteNotebook)
+
+	tags := api.Group("/tags")
+	tags.Use(middleware.JWTMiddleware())
+	tags.GET("", noteHandler.GetTags)
+	tags.DELETE("/:id", noteHandler.DeleteTag)
+
+	// MCP Routes (Protected) ให้บริการ MCP server ผ่าน streamable HTTP ที่ /mcp และ path ย่อยทั้งหมด
+	// tool ทำงานในนามผู้ใช้จาก JWT
+	mcpGroup := e.Group("/mcp")
+	mcpGroup.Use(middleware.JWTMiddleware())
+	mcpGroup.Any("", echo.WrapHandler(mcpHandler))
+	mcpGroup.Any("/*", echo.WrapHandler(mcpHandler))
 
 	// Admin Routes
 	admin := api.Group("/admin")
 	admin.Use(middleware.JWTMiddleware())
 	admin.Use(middleware.AdminMiddleware)
 
-	// TODO: Add admin routes for user management
+	admin.GET("/users", adminHandler.ListUsers)
+	admin.POST("/users/:id/deactivate", adminHandler.DeactivateUser)
+	admin.PUT("/users/:id/role", adminHandler.UpdateUserRole)
+	admin.GET("/users/:id/login-history", adminHandler.GetUserLoginHistory)
 }
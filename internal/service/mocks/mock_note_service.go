@@ -7,6 +7,7 @@ package mocks
 import (
 	reflect "reflect"
 
+	service "github.com/Napat/mcpserver-demo/internal/service"
 	models "github.com/Napat/mcpserver-demo/models"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +35,21 @@ func (m *MockINoteService) EXPECT() *MockINoteServiceMockRecorder {
 	return m.recorder
 }
 
+// AddTags mocks base method.
+func (m *MockINoteService) AddTags(id, userID uint, names []string) (*models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTags", id, userID, names)
+	ret0, _ := ret[0].(*models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTags indicates an expected call of AddTags.
+func (mr *MockINoteServiceMockRecorder) AddTags(id, userID, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTags", reflect.TypeOf((*MockINoteService)(nil).AddTags), id, userID, names)
+}
+
 // Create mocks base method.
 func (m *MockINoteService) Create(note *models.Note) error {
 	m.ctrl.T.Helper()
@@ -62,6 +78,35 @@ func (mr *MockINoteServiceMockRecorder) Delete(id, userID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockINoteService)(nil).Delete), id, userID)
 }
 
+// DeleteTag mocks base method.
+func (m *MockINoteService) DeleteTag(tagID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", tagID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockINoteServiceMockRecorder) DeleteTag(tagID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockINoteService)(nil).DeleteTag), tagID, userID)
+}
+
+// DiffRevisions mocks base method.
+func (m *MockINoteService) DiffRevisions(id, userID uint, from, to int) (*models.NoteDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", id, userID, from, to)
+	ret0, _ := ret[0].(*models.NoteDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockINoteServiceMockRecorder) DiffRevisions(id, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockINoteService)(nil).DiffRevisions), id, userID, from, to)
+}
+
 // GetAllByUserID mocks base method.
 func (m *MockINoteService) GetAllByUserID(userID uint) ([]models.Note, error) {
 	m.ctrl.T.Helper()
@@ -92,6 +137,126 @@ func (mr *MockINoteServiceMockRecorder) GetByID(id, userID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockINoteService)(nil).GetByID), id, userID)
 }
 
+// GetRevision mocks base method.
+func (m *MockINoteService) GetRevision(id, userID uint, revision int) (*models.NoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", id, userID, revision)
+	ret0, _ := ret[0].(*models.NoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockINoteServiceMockRecorder) GetRevision(id, userID, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockINoteService)(nil).GetRevision), id, userID, revision)
+}
+
+// GetTags mocks base method.
+func (m *MockINoteService) GetTags(userID uint) ([]models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", userID)
+	ret0, _ := ret[0].([]models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockINoteServiceMockRecorder) GetTags(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockINoteService)(nil).GetTags), userID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockINoteService) ListByUserID(userID uint, params service.NoteListParams) (*models.NotePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", userID, params)
+	ret0, _ := ret[0].(*models.NotePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockINoteServiceMockRecorder) ListByUserID(userID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockINoteService)(nil).ListByUserID), userID, params)
+}
+
+// ListRevisions mocks base method.
+func (m *MockINoteService) ListRevisions(id, userID uint) ([]models.NoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", id, userID)
+	ret0, _ := ret[0].([]models.NoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockINoteServiceMockRecorder) ListRevisions(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockINoteService)(nil).ListRevisions), id, userID)
+}
+
+// MoveToNotebook mocks base method.
+func (m *MockINoteService) MoveToNotebook(id, userID uint, notebookID *uint) (*models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToNotebook", id, userID, notebookID)
+	ret0, _ := ret[0].(*models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToNotebook indicates an expected call of MoveToNotebook.
+func (mr *MockINoteServiceMockRecorder) MoveToNotebook(id, userID, notebookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToNotebook", reflect.TypeOf((*MockINoteService)(nil).MoveToNotebook), id, userID, notebookID)
+}
+
+// RemoveTag mocks base method.
+func (m *MockINoteService) RemoveTag(id, userID, tagID uint) (*models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTag", id, userID, tagID)
+	ret0, _ := ret[0].(*models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTag indicates an expected call of RemoveTag.
+func (mr *MockINoteServiceMockRecorder) RemoveTag(id, userID, tagID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTag", reflect.TypeOf((*MockINoteService)(nil).RemoveTag), id, userID, tagID)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockINoteService) RestoreRevision(id, userID uint, revision int) (*models.Note, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", id, userID, revision)
+	ret0, _ := ret[0].(*models.Note)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockINoteServiceMockRecorder) RestoreRevision(id, userID, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockINoteService)(nil).RestoreRevision), id, userID, revision)
+}
+
+// Search mocks base method.
+func (m *MockINoteService) Search(userID uint, query string, limit int) ([]models.NoteSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", userID, query, limit)
+	ret0, _ := ret[0].([]models.NoteSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockINoteServiceMockRecorder) Search(userID, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockINoteService)(nil).Search), userID, query, limit)
+}
+
 // Update mocks base method.
 func (m *MockINoteService) Update(note *models.Note, userID uint) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./note_share_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINoteShareService is a mock of INoteShareService interface.
+type MockINoteShareService struct {
+	ctrl     *gomock.Controller
+	recorder *MockINoteShareServiceMockRecorder
+}
+
+// MockINoteShareServiceMockRecorder is the mock recorder for MockINoteShareService.
+type MockINoteShareServiceMockRecorder struct {
+	mock *MockINoteShareService
+}
+
+// NewMockINoteShareService creates a new mock instance.
+func NewMockINoteShareService(ctrl *gomock.Controller) *MockINoteShareService {
+	mock := &MockINoteShareService{ctrl: ctrl}
+	mock.recorder = &MockINoteShareServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINoteShareService) EXPECT() *MockINoteShareServiceMockRecorder {
+	return m.recorder
+}
+
+// ListShares mocks base method.
+func (m *MockINoteShareService) ListShares(noteID, ownerID uint) ([]models.NoteShareInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", noteID, ownerID)
+	ret0, _ := ret[0].([]models.NoteShareInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockINoteShareServiceMockRecorder) ListShares(noteID, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockINoteShareService)(nil).ListShares), noteID, ownerID)
+}
+
+// Share mocks base method.
+func (m *MockINoteShareService) Share(noteID, ownerID uint, email string, permission models.NotePermission) (*models.NoteShareInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Share", noteID, ownerID, email, permission)
+	ret0, _ := ret[0].(*models.NoteShareInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Share indicates an expected call of Share.
+func (mr *MockINoteShareServiceMockRecorder) Share(noteID, ownerID, email, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*MockINoteShareService)(nil).Share), noteID, ownerID, email, permission)
+}
+
+// SharedWithMe mocks base method.
+func (m *MockINoteShareService) SharedWithMe(userID uint) ([]models.SharedNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedWithMe", userID)
+	ret0, _ := ret[0].([]models.SharedNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SharedWithMe indicates an expected call of SharedWithMe.
+func (mr *MockINoteShareServiceMockRecorder) SharedWithMe(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedWithMe", reflect.TypeOf((*MockINoteShareService)(nil).SharedWithMe), userID)
+}
+
+// Unshare mocks base method.
+func (m *MockINoteShareService) Unshare(noteID, callerID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unshare", noteID, callerID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unshare indicates an expected call of Unshare.
+func (mr *MockINoteShareServiceMockRecorder) Unshare(noteID, callerID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unshare", reflect.TypeOf((*MockINoteShareService)(nil).Unshare), noteID, callerID, userID)
+}
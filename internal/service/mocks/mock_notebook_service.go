@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./notebook_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Napat/mcpserver-demo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINotebookService is a mock of INotebookService interface.
+type MockINotebookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotebookServiceMockRecorder
+}
+
+// MockINotebookServiceMockRecorder is the mock recorder for MockINotebookService.
+type MockINotebookServiceMockRecorder struct {
+	mock *MockINotebookService
+}
+
+// NewMockINotebookService creates a new mock instance.
+func NewMockINotebookService(ctrl *gomock.Controller) *MockINotebookService {
+	mock := &MockINotebookService{ctrl: ctrl}
+	mock.recorder = &MockINotebookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotebookService) EXPECT() *MockINotebookServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockINotebookService) Create(notebook *models.Notebook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", notebook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockINotebookServiceMockRecorder) Create(notebook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockINotebookService)(nil).Create), notebook)
+}
+
+// Delete mocks base method.
+func (m *MockINotebookService) Delete(id, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockINotebookServiceMockRecorder) Delete(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockINotebookService)(nil).Delete), id, userID)
+}
+
+// GetAllByUserID mocks base method.
+func (m *MockINotebookService) GetAllByUserID(userID uint) ([]models.Notebook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserID", userID)
+	ret0, _ := ret[0].([]models.Notebook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserID indicates an expected call of GetAllByUserID.
+func (mr *MockINotebookServiceMockRecorder) GetAllByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockINotebookService)(nil).GetAllByUserID), userID)
+}
+
+// GetByID mocks base method.
+func (m *MockINotebookService) GetByID(id, userID uint) (*models.Notebook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id, userID)
+	ret0, _ := ret[0].(*models.Notebook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockINotebookServiceMockRecorder) GetByID(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockINotebookService)(nil).GetByID), id, userID)
+}
+
+// Update mocks base method.
+func (m *MockINotebookService) Update(notebook *models.Notebook, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", notebook, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockINotebookServiceMockRecorder) Update(notebook, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockINotebookService)(nil).Update), notebook, userID)
+}
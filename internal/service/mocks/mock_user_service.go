@@ -35,6 +35,36 @@ func (m *MockIUserService) EXPECT() *MockIUserServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangeRole mocks base method.
+func (m *MockIUserService) ChangeRole(id uint, role models.UserRole, actorID uint, actorRole models.UserRole) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", id, role, actorID, actorRole)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockIUserServiceMockRecorder) ChangeRole(id, role, actorID, actorRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockIUserService)(nil).ChangeRole), id, role, actorID, actorRole)
+}
+
+// DeactivateUser mocks base method.
+func (m *MockIUserService) DeactivateUser(id, actorID uint, actorRole models.UserRole) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUser", id, actorID, actorRole)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateUser indicates an expected call of DeactivateUser.
+func (mr *MockIUserServiceMockRecorder) DeactivateUser(id, actorID, actorRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUser", reflect.TypeOf((*MockIUserService)(nil).DeactivateUser), id, actorID, actorRole)
+}
+
 // GetLoginHistory mocks base method.
 func (m *MockIUserService) GetLoginHistory(userID uint, limit int) ([]models.LoginHistory, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +110,21 @@ func (mr *MockIUserServiceMockRecorder) GetUserByID(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockIUserService)(nil).GetUserByID), id)
 }
 
+// ListUsers mocks base method.
+func (m *MockIUserService) ListUsers() ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers")
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockIUserServiceMockRecorder) ListUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockIUserService)(nil).ListUsers))
+}
+
 // Login mocks base method.
 func (m *MockIUserService) Login(email, password string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change in a unified diff
+const diffContextLines = 3
+
+// diffRevisions compares the content of two revisions of the same note
+func diffRevisions(older, newer *models.NoteRevision) *models.NoteDiff {
+	a := contentLines(older.Content)
+	b := contentLines(newer.Content)
+
+	lines := []models.NoteDiffLine{}
+	for _, op := range difflib.NewMatcher(a, b).GetOpCodes() {
+		if op.Tag == 'e' {
+			for i := op.I1; i < op.I2; i++ {
+				j := op.J1 + i - op.I1
+				lines = append(lines, models.NoteDiffLine{Op: models.NoteDiffEqual, OldLine: i + 1, NewLine: j + 1, Text: trimNewline(a[i])})
+			}
+			continue
+		}
+		// A replaced block is shown as its deleted lines followed by its inserted lines
+		for i := op.I1; i < op.I2; i++ {
+			lines = append(lines, models.NoteDiffLine{Op: models.NoteDiffDelete, OldLine: i + 1, Text: trimNewline(a[i])})
+		}
+		for j := op.J1; j < op.J2; j++ {
+			lines = append(lines, models.NoteDiffLine{Op: models.NoteDiffInsert, NewLine: j + 1, Text: trimNewline(b[j])})
+		}
+	}
+
+	unified, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        b,
+		FromFile: fmt.Sprintf("revision %d", older.Revision),
+		ToFile:   fmt.Sprintf("revision %d", newer.Revision),
+		Context:  diffContextLines,
+	})
+
+	return &models.NoteDiff{
+		NoteID:   newer.NoteID,
+		From:     older.Revision,
+		To:       newer.Revision,
+		OldTitle: older.Title,
+		NewTitle: newer.Title,
+		Lines:    lines,
+		Unified:  unified,
+	}
+}
+
+// contentLines splits content into lines that each end with a newline, as the unified diff expects
+// Empty content has no lines
+func contentLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+// trimNewline removes the newline that contentLines keeps at the end of a line
+func trimNewline(line string) string {
+	return strings.TrimSuffix(line, "\n")
+}
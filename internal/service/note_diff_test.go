@@ -0,0 +1,104 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Napat/mcpserver-demo/models"
+)
+
+func TestContentLines(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "empty", content: "", want: nil},
+		{name: "no trailing newline", content: "a\nb", want: []string{"a\n", "b\n"}},
+		{name: "trailing newline", content: "a\nb\n", want: []string{"a\n", "b\n"}},
+		{name: "windows line endings", content: "a\r\nb\r\n", want: []string{"a\n", "b\n"}},
+		{name: "blank lines kept", content: "a\n\nb", want: []string{"a\n", "\n", "b\n"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := contentLines(tc.content); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("contentLines(%q) = %q, want %q", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	eq := func(old, new int, text string) models.NoteDiffLine {
+		return models.NoteDiffLine{Op: models.NoteDiffEqual, OldLine: old, NewLine: new, Text: text}
+	}
+	del := func(old int, text string) models.NoteDiffLine {
+		return models.NoteDiffLine{Op: models.NoteDiffDelete, OldLine: old, Text: text}
+	}
+	ins := func(new int, text string) models.NoteDiffLine {
+		return models.NoteDiffLine{Op: models.NoteDiffInsert, NewLine: new, Text: text}
+	}
+
+	cases := []struct {
+		name        string
+		old, new    string
+		wantLines   []models.NoteDiffLine
+		wantUnified string
+	}{
+		{
+			name:        "identical",
+			old:         "a\nb",
+			new:         "a\nb\n",
+			wantLines:   []models.NoteDiffLine{eq(1, 1, "a"), eq(2, 2, "b")},
+			wantUnified: "",
+		},
+		{
+			name:      "replaced line",
+			old:       "- milk\n- eggs\n- coffee",
+			new:       "- milk\n- bread\n- coffee",
+			wantLines: []models.NoteDiffLine{eq(1, 1, "- milk"), del(2, "- eggs"), ins(2, "- bread"), eq(3, 3, "- coffee")},
+			wantUnified: "--- revision 1\n+++ revision 2\n@@ -1,3 +1,3 @@\n" +
+				" - milk\n-- eggs\n+- bread\n - coffee\n",
+		},
+		{
+			name:        "from empty content",
+			old:         "",
+			new:         "ซื้อนม\nไข่",
+			wantLines:   []models.NoteDiffLine{ins(1, "ซื้อนม"), ins(2, "ไข่")},
+			wantUnified: "--- revision 1\n+++ revision 2\n@@ -0,0 +1,2 @@\n+ซื้อนม\n+ไข่\n",
+		},
+		{
+			name:        "to empty content",
+			old:         "a\nb",
+			new:         "",
+			wantLines:   []models.NoteDiffLine{del(1, "a"), del(2, "b")},
+			wantUnified: "--- revision 1\n+++ revision 2\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name:        "line endings only",
+			old:         "a\r\nb",
+			new:         "a\nb",
+			wantLines:   []models.NoteDiffLine{eq(1, 1, "a"), eq(2, 2, "b")},
+			wantUnified: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			older := &models.NoteRevision{NoteID: 9, Revision: 1, Title: "Old", Content: tc.old}
+			newer := &models.NoteRevision{NoteID: 9, Revision: 2, Title: "New", Content: tc.new}
+
+			diff := diffRevisions(older, newer)
+			if diff.NoteID != 9 || diff.From != 1 || diff.To != 2 || diff.OldTitle != "Old" || diff.NewTitle != "New" {
+				t.Errorf("diff header = %+v", *diff)
+			}
+			if !reflect.DeepEqual(diff.Lines, tc.wantLines) {
+				t.Errorf("lines = %+v, want %+v", diff.Lines, tc.wantLines)
+			}
+			if diff.Unified != tc.wantUnified {
+				t.Errorf("unified =\n%s\nwant\n%s", diff.Unified, tc.wantUnified)
+			}
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Napat/mcpserver-demo/internal/repository"
 	"github.com/Napat/mcpserver-demo/models"
@@ -15,36 +21,119 @@ type INoteService interface {
 	Create(note *models.Note) error
 	GetByID(id, userID uint) (*models.Note, error)
 	GetAllByUserID(userID uint) ([]models.Note, error)
+	ListByUserID(userID uint, params NoteListParams) (*models.NotePage, error)
+	Search(userID uint, query string, limit int) ([]models.NoteSearchResult, error)
+
+	// Tags and notebooks
+	GetTags(userID uint) ([]models.Tag, error)
+	DeleteTag(tagID, userID uint) error
+	AddTags(id, userID uint, names []string) (*models.Note, error)
+	RemoveTag(id, userID, tagID uint) (*models.Note, error)
+	MoveToNotebook(id, userID uint, notebookID *uint) (*models.Note, error)
 	Update(note *models.Note, userID uint) error
 	Delete(id, userID uint) error
+
+	// Revisions
+	ListRevisions(id, userID uint) ([]models.NoteRevision, error)
+	GetRevision(id, userID uint, revision int) (*models.NoteRevision, error)
+	DiffRevisions(id, userID uint, from, to int) (*models.NoteDiff, error)
+	RestoreRevision(id, userID uint, revision int) (*models.Note, error)
+}
+
+const (
+	// DefaultNotePageSize is the page size used when NoteListParams.Limit is not set
+	DefaultNotePageSize = 20
+	// MaxNotePageSize is the largest page size that can be requested
+	MaxNotePageSize = 100
+
+	// DefaultNoteSearchLimit is the number of search results returned when no limit is given
+	DefaultNoteSearchLimit = 20
+	// MaxNoteSearchLimit is the largest number of search results that can be requested
+	MaxNoteSearchLimit = 50
+	// maxNoteSearchTerms is the number of words of a search query that are used
+	maxNoteSearchTerms = 10
+
+	// maxTagNameLength is the longest tag name in characters
+	maxTagNameLength = 50
+	// maxTagsPerRequest is the number of tags that can be added to a note at once
+	maxTagsPerRequest = 20
+)
+
+// NoteListParams are the options for listing a user's notes
+type NoteListParams struct {
+	Filter     repository.NoteFilter
+	SortBy     repository.NoteSortField
+	Descending bool
+	Limit      int
+	// Cursor is NextCursor or PrevCursor of a previous page listed with the same sort order
+	Cursor string
+}
+
+// noteCursor is the decoded form of NotePage.NextCursor and NotePage.PrevCursor
+type noteCursor struct {
+	SortBy     repository.NoteSortField `json:"sort"`
+	Descending bool                     `json:"desc"`
+	Backward   bool                     `json:"back,omitempty"`
+	Key        repository.NoteKey       `json:"key"`
 }
 
 // NoteService struct for handling note business logic
 type NoteService struct {
-	noteRepo repository.INoteRepository
-	logger   *zap.Logger
+	noteRepo     repository.INoteRepository
+	tagRepo      repository.ITagRepository
+	notebookRepo repository.INotebookRepository
+	shareRepo    repository.INoteShareRepository
+	revisionRepo repository.INoteRevisionRepository
+	eventRepo    repository.INoteEventRepository
+	logger       *zap.Logger
 }
 
 // NewNoteService creates a new instance of NoteService
-func NewNoteService(noteRepo repository.INoteRepository, logger *zap.Logger) INoteService {
+// eventRepo may be nil, in which case no change events are published
+func NewNoteService(noteRepo repository.INoteRepository, tagRepo repository.ITagRepository, notebookRepo repository.INotebookRepository, shareRepo repository.INoteShareRepository, revisionRepo repository.INoteRevisionRepository, eventRepo repository.INoteEventRepository, logger *zap.Logger) INoteService {
 	return &NoteService{
-		noteRepo: noteRepo,
-		logger:   logger,
+		noteRepo:     noteRepo,
+		tagRepo:      tagRepo,
+		notebookRepo: notebookRepo,
+		shareRepo:    shareRepo,
+		revisionRepo: revisionRepo,
+		eventRepo:    eventRepo,
+		logger:       logger,
 	}
 }
 
 // Create creates a new note
 func (s *NoteService) Create(note *models.Note) error {
-	return s.noteRepo.Create(note)
+	if err := s.noteRepo.Create(note); err != nil {
+		return err
+	}
+
+	s.publish(models.NoteEventCreated, note.ID, note.UserID)
+	return nil
 }
 
 // GetByID retrieves a note by ID and checks access permissions
+// The owner and users the note is shared with can read it
 func (s *NoteService) GetByID(id, userID uint) (*models.Note, error) {
 	note, err := s.noteRepo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.checkAccess(note, userID, models.NotePermissionRead); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// getOwned retrieves a note that only its owner may use, such as for tagging or filing it
+func (s *NoteService) getOwned(id, userID uint) (*models.Note, error) {
+	note, err := s.noteRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
 	if note.UserID != userID {
 		return nil, errors.New("unauthorized access to note")
 	}
@@ -52,26 +141,199 @@ func (s *NoteService) GetByID(id, userID uint) (*models.Note, error) {
 	return note, nil
 }
 
+// checkAccess checks that userID owns the note or was granted at least the needed permission
+func (s *NoteService) checkAccess(note *models.Note, userID uint, needed models.NotePermission) error {
+	if note.UserID == userID {
+		return nil
+	}
+
+	share, err := s.shareRepo.Find(note.ID, userID)
+	if err != nil {
+		if err.Error() == "share not found" {
+			return errors.New("unauthorized access to note")
+		}
+		return err
+	}
+
+	if needed == models.NotePermissionEdit && share.Permission != models.NotePermissionEdit {
+		return errors.New("unauthorized access to note")
+	}
+	return nil
+}
+
 // GetAllByUserID retrieves all notes for a user
 func (s *NoteService) GetAllByUserID(userID uint) ([]models.Note, error) {
 	return s.noteRepo.FindByUserID(userID)
 }
 
+// ListByUserID retrieves one page of a user's notes together with the total number of matching notes
+func (s *NoteService) ListByUserID(userID uint, params NoteListParams) (*models.NotePage, error) {
+	if params.SortBy == "" {
+		params.SortBy = repository.NoteSortCreatedAt
+	}
+	tags, err := normalizeTagNames(params.Filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	params.Filter.Tags = tags
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultNotePageSize
+	}
+	if limit > MaxNotePageSize {
+		limit = MaxNotePageSize
+	}
+
+	// Read one extra note to find out whether another page follows in the paging direction
+	query := repository.NoteListQuery{
+		UserID:     userID,
+		Filter:     params.Filter,
+		SortBy:     params.SortBy,
+		Descending: params.Descending,
+		Limit:      limit + 1,
+	}
+
+	var cursor *noteCursor
+	if params.Cursor != "" {
+		decoded, err := decodeNoteCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if decoded.SortBy != params.SortBy || decoded.Descending != params.Descending {
+			return nil, errors.New("cursor does not match the sort order")
+		}
+		cursor = decoded
+		if cursor.Backward {
+			query.Before = &cursor.Key
+		} else {
+			query.After = &cursor.Key
+		}
+	}
+	backward := cursor != nil && cursor.Backward
+
+	notes, err := s.noteRepo.FindPage(query)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(notes) > limit
+	if hasMore {
+		if backward {
+			notes = notes[len(notes)-limit:]
+		} else {
+			notes = notes[:limit]
+		}
+	}
+
+	total, err := s.noteRepo.Count(userID, params.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.NotePage{Notes: notes, Total: total, Limit: limit}
+	if len(notes) == 0 {
+		return page, nil
+	}
+
+	// A cursor only exists because the client came from the neighbouring page in that direction
+	hasNext := (!backward && hasMore) || backward
+	hasPrev := (backward && hasMore) || (!backward && cursor != nil)
+	if hasNext {
+		page.NextCursor = encodeNoteCursor(noteCursor{
+			SortBy:     params.SortBy,
+			Descending: params.Descending,
+			Key:        repository.NoteKeyOf(&notes[len(notes)-1], params.SortBy),
+		})
+	}
+	if hasPrev {
+		page.PrevCursor = encodeNoteCursor(noteCursor{
+			SortBy:     params.SortBy,
+			Descending: params.Descending,
+			Backward:   true,
+			Key:        repository.NoteKeyOf(&notes[0], params.SortBy),
+		})
+	}
+	return page, nil
+}
+
+// Search finds a user's notes that contain every word of query, most relevant first
+func (s *NoteService) Search(userID uint, query string, limit int) ([]models.NoteSearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, errors.New("search query is required")
+	}
+	if limit <= 0 {
+		limit = DefaultNoteSearchLimit
+	}
+	if limit > MaxNoteSearchLimit {
+		limit = MaxNoteSearchLimit
+	}
+
+	hits, err := s.noteRepo.Search(userID, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.NoteSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, models.NoteSearchResult{
+			Note:    hit.Note,
+			Rank:    hit.Rank,
+			Snippet: highlightSnippet(hit.Content, terms),
+		})
+	}
+	return results, nil
+}
+
+// encodeNoteCursor encodes a cursor as an opaque URL-safe string
+func encodeNoteCursor(cursor noteCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeNoteCursor decodes a cursor created by encodeNoteCursor
+func decodeNoteCursor(value string) (*noteCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor noteCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Key.ID == 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
 // Update updates a note and checks access permissions
+// The owner and users with edit permission can update it; note is refreshed with the stored note
 func (s *NoteService) Update(note *models.Note, userID uint) error {
 	existing, err := s.noteRepo.FindByID(note.ID)
 	if err != nil {
 		return err
 	}
 
-	if existing.UserID != userID {
-		return errors.New("unauthorized access to note")
+	if err := s.checkAccess(existing, userID, models.NotePermissionEdit); err != nil {
+		return err
+	}
+
+	note.LastEditedByID = &userID
+	if err := s.noteRepo.Update(note); err != nil {
+		return err
 	}
 
-	return s.noteRepo.Update(note)
+	s.publish(models.NoteEventUpdated, note.ID, existing.UserID)
+
+	updated, err := s.noteRepo.FindByID(note.ID)
+	if err != nil {
+		return err
+	}
+	*note = *updated
+	return nil
 }
 
 // Delete removes a note and checks access permissions
+// Only the owner can delete a note, even when it is shared with edit permission
 func (s *NoteService) Delete(id, userID uint) error {
 	existing, err := s.noteRepo.FindByID(id)
 	if err != nil {
@@ -82,5 +344,228 @@ func (s *NoteService) Delete(id, userID uint) error {
 		return errors.New("unauthorized access to note")
 	}
 
-	return s.noteRepo.Delete(id)
+	if err := s.noteRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.publish(models.NoteEventDeleted, id, existing.UserID)
+	return nil
+}
+
+// GetTags retrieves all tags of a user
+func (s *NoteService) GetTags(userID uint) ([]models.Tag, error) {
+	return s.tagRepo.FindByUserID(userID)
+}
+
+// DeleteTag removes a tag from all notes of its owner and deletes it
+func (s *NoteService) DeleteTag(tagID, userID uint) error {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		return err
+	}
+
+	if tag.UserID != userID {
+		return errors.New("unauthorized access to tag")
+	}
+
+	return s.tagRepo.Delete(tagID)
+}
+
+// AddTags attaches tags to a note by name, creating the tags the user does not have yet
+func (s *NoteService) AddTags(id, userID uint, names []string) (*models.Note, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 || len(names) > maxTagsPerRequest {
+		return nil, errors.New("invalid tag name")
+	}
+
+	if _, err := s.getOwned(id, userID); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindOrCreate(userID, names)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.noteRepo.AddTags(id, tags); err != nil {
+		return nil, err
+	}
+
+	return s.changed(id, userID)
+}
+
+// RemoveTag detaches a tag from a note
+func (s *NoteService) RemoveTag(id, userID, tagID uint) (*models.Note, error) {
+	if _, err := s.getOwned(id, userID); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, errors.New("unauthorized access to tag")
+	}
+
+	if err := s.noteRepo.RemoveTag(id, tagID); err != nil {
+		return nil, err
+	}
+
+	return s.changed(id, userID)
+}
+
+// MoveToNotebook files a note in one of the user's notebooks, or takes it out of its notebook when notebookID is nil
+func (s *NoteService) MoveToNotebook(id, userID uint, notebookID *uint) (*models.Note, error) {
+	if _, err := s.getOwned(id, userID); err != nil {
+		return nil, err
+	}
+
+	if notebookID != nil {
+		notebook, err := s.notebookRepo.FindByID(*notebookID)
+		if err != nil {
+			return nil, err
+		}
+		if notebook.UserID != userID {
+			return nil, errors.New("unauthorized access to notebook")
+		}
+	}
+
+	if err := s.noteRepo.UpdateNotebook(id, notebookID); err != nil {
+		return nil, err
+	}
+
+	return s.changed(id, userID)
+}
+
+// ListRevisions retrieves every revision of a note, newest first
+// Anyone who can read the note can read its history
+func (s *NoteService) ListRevisions(id, userID uint) ([]models.NoteRevision, error) {
+	if _, err := s.GetByID(id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.revisionRepo.FindByNoteID(id)
+}
+
+// GetRevision retrieves one revision of a note
+func (s *NoteService) GetRevision(id, userID uint, revision int) (*models.NoteRevision, error) {
+	if _, err := s.GetByID(id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.revisionRepo.FindByRevision(id, revision)
+}
+
+// DiffRevisions compares two revisions of a note line by line
+// A to of 0 means the latest revision and a from of 0 means the revision before to;
+// revision 0 itself is the empty note before the first revision
+func (s *NoteService) DiffRevisions(id, userID uint, from, to int) (*models.NoteDiff, error) {
+	if from < 0 || to < 0 {
+		return nil, errors.New("invalid revision")
+	}
+
+	if _, err := s.GetByID(id, userID); err != nil {
+		return nil, err
+	}
+
+	var newer *models.NoteRevision
+	var err error
+	if to == 0 {
+		newer, err = s.revisionRepo.FindLatest(id)
+	} else {
+		newer, err = s.revisionRepo.FindByRevision(id, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if from == 0 {
+		from = newer.Revision - 1
+	}
+	older := &models.NoteRevision{NoteID: id}
+	if from > 0 {
+		if older, err = s.revisionRepo.FindByRevision(id, from); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffRevisions(older, newer), nil
+}
+
+// RestoreRevision puts the title and content of an old revision back into a note
+// The restore is stored as a new revision, so the history is never rewritten; it needs edit permission
+func (s *NoteService) RestoreRevision(id, userID uint, revision int) (*models.Note, error) {
+	note, err := s.noteRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAccess(note, userID, models.NotePermissionEdit); err != nil {
+		return nil, err
+	}
+
+	old, err := s.revisionRepo.FindByRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Title = old.Title
+	note.Content = old.Content
+	note.LastEditedByID = &userID
+	if err := s.noteRepo.Restore(note, old.Revision); err != nil {
+		return nil, err
+	}
+
+	return s.changed(id, note.UserID)
+}
+
+// changed publishes an update event for a note and returns the note as it is now
+func (s *NoteService) changed(id, userID uint) (*models.Note, error) {
+	s.publish(models.NoteEventUpdated, id, userID)
+	return s.noteRepo.FindByID(id)
+}
+
+// normalizeTagNames trims, lower-cases and de-duplicates tag names
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+			return nil, errors.New("invalid tag name")
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+// publish sends a change event for a note
+// The change is already stored, so a failure is only logged and never returned to the caller
+func (s *NoteService) publish(eventType models.NoteEventType, noteID, userID uint) {
+	if s.eventRepo == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	event := models.NoteEvent{
+		Type:       eventType,
+		NoteID:     noteID,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+	}
+	if err := s.eventRepo.Publish(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish note event",
+			zap.String("type", string(eventType)),
+			zap.Uint("note_id", noteID),
+			zap.Error(err),
+		)
+	}
 }
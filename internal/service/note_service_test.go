@@ -0,0 +1,299 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/internal/repository"
+	"github.com/Napat/mcpserver-demo/internal/repository/mocks"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// pagingNotes has notes with equal created_at, updated_at and title values so that paging must break ties by ID
+var pagingNotes = []models.Note{
+	{ID: 1, Title: "b", CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)},
+	{ID: 2, Title: "a", CreatedAt: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)},
+	{ID: 3, Title: "b", CreatedAt: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)},
+	{ID: 4, Title: "c", CreatedAt: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)},
+	{ID: 5, Title: "a", CreatedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC)},
+}
+
+// compareNoteKeys orders two keys by the sort field and then by ID, like the SQL of NoteRepository.FindPage
+func compareNoteKeys(a, b repository.NoteKey, field repository.NoteSortField) int {
+	switch field {
+	case repository.NoteSortTitle:
+		if a.Title != b.Title {
+			if a.Title < b.Title {
+				return -1
+			}
+			return 1
+		}
+	case repository.NoteSortUpdatedAt:
+		if c := a.UpdatedAt.Compare(b.UpdatedAt); c != 0 {
+			return c
+		}
+	default:
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a.ID < b.ID:
+		return -1
+	case a.ID > b.ID:
+		return 1
+	}
+	return 0
+}
+
+// findPage mimics NoteRepository.FindPage on notes held in memory
+func findPage(notes []models.Note) func(repository.NoteListQuery) ([]models.Note, error) {
+	return func(query repository.NoteListQuery) ([]models.Note, error) {
+		key, backward := query.After, false
+		if query.Before != nil {
+			key, backward = query.Before, true
+		}
+		descending := query.Descending != backward
+
+		sorted := append([]models.Note{}, notes...)
+		sort.Slice(sorted, func(i, j int) bool {
+			c := compareNoteKeys(repository.NoteKeyOf(&sorted[i], query.SortBy), repository.NoteKeyOf(&sorted[j], query.SortBy), query.SortBy)
+			return (c < 0) != descending
+		})
+
+		page := []models.Note{}
+		for i := range sorted {
+			if key != nil {
+				c := compareNoteKeys(repository.NoteKeyOf(&sorted[i], query.SortBy), *key, query.SortBy)
+				if (descending && c >= 0) || (!descending && c <= 0) {
+					continue
+				}
+			}
+			if len(page) == query.Limit {
+				break
+			}
+			page = append(page, sorted[i])
+		}
+
+		if backward {
+			for i, j := 0, len(page)-1; i < j; i, j = i+1, j-1 {
+				page[i], page[j] = page[j], page[i]
+			}
+		}
+		return page, nil
+	}
+}
+
+// newPagingService creates a NoteService whose repository pages through pagingNotes
+func newPagingService(t *testing.T) *NoteService {
+	ctrl := gomock.NewController(t)
+	noteRepo := mocks.NewMockINoteRepository(ctrl)
+	noteRepo.EXPECT().FindPage(gomock.Any()).DoAndReturn(findPage(pagingNotes)).AnyTimes()
+	noteRepo.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(len(pagingNotes)), nil).AnyTimes()
+
+	return NewNoteService(noteRepo, nil, nil, nil, nil, nil, zap.NewNop()).(*NoteService)
+}
+
+// noteIDs returns the IDs of notes in order
+func noteIDs(notes []models.Note) []uint {
+	ids := []uint{}
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	return ids
+}
+
+func TestListByUserIDPaging(t *testing.T) {
+	cases := []struct {
+		name       string
+		sortBy     repository.NoteSortField
+		descending bool
+		want       [][]uint
+	}{
+		{name: "created_at desc with ties", sortBy: repository.NoteSortCreatedAt, descending: true, want: [][]uint{{5, 4}, {3, 2}, {1}}},
+		{name: "created_at asc with ties", sortBy: repository.NoteSortCreatedAt, want: [][]uint{{1, 2}, {3, 4}, {5}}},
+		{name: "title asc with ties", sortBy: repository.NoteSortTitle, want: [][]uint{{2, 5}, {1, 3}, {4}}},
+		{name: "updated_at desc all equal", sortBy: repository.NoteSortUpdatedAt, descending: true, want: [][]uint{{5, 4}, {3, 2}, {1}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newPagingService(t)
+			params := NoteListParams{SortBy: tc.sortBy, Descending: tc.descending, Limit: 2}
+
+			// Forward through every page with NextCursor
+			var pages []*models.NotePage
+			for {
+				page, err := s.ListByUserID(1, params)
+				if err != nil {
+					t.Fatalf("ListByUserID failed: %v", err)
+				}
+				pages = append(pages, page)
+				if page.NextCursor == "" {
+					break
+				}
+				if len(pages) > len(tc.want) {
+					t.Fatalf("more than %d pages", len(tc.want))
+				}
+				params.Cursor = page.NextCursor
+			}
+
+			if len(pages) != len(tc.want) {
+				t.Fatalf("got %d pages, want %d", len(pages), len(tc.want))
+			}
+			for i, page := range pages {
+				if got := noteIDs(page.Notes); !reflect.DeepEqual(got, tc.want[i]) {
+					t.Errorf("page %d = %v, want %v", i+1, got, tc.want[i])
+				}
+				if page.Total != int64(len(pagingNotes)) {
+					t.Errorf("page %d total = %d, want %d", i+1, page.Total, len(pagingNotes))
+				}
+			}
+			if pages[0].PrevCursor != "" {
+				t.Errorf("first page has a PrevCursor")
+			}
+
+			// Back from the last page with PrevCursor returns the same pages
+			params.Cursor = pages[len(pages)-1].PrevCursor
+			for i := len(pages) - 2; i >= 0; i-- {
+				page, err := s.ListByUserID(1, params)
+				if err != nil {
+					t.Fatalf("ListByUserID backwards failed: %v", err)
+				}
+				if got := noteIDs(page.Notes); !reflect.DeepEqual(got, tc.want[i]) {
+					t.Errorf("page %d backwards = %v, want %v", i+1, got, tc.want[i])
+				}
+				if (page.PrevCursor == "") != (i == 0) {
+					t.Errorf("page %d backwards PrevCursor = %q", i+1, page.PrevCursor)
+				}
+				params.Cursor = page.PrevCursor
+			}
+		})
+	}
+}
+
+func TestListByUserIDInvalidCursor(t *testing.T) {
+	key := repository.NoteKey{ID: 3, CreatedAt: pagingNotes[2].CreatedAt}
+
+	cases := []struct {
+		name    string
+		params  NoteListParams
+		wantErr string
+	}{
+		{name: "not base64", params: NoteListParams{Cursor: "%%%"}, wantErr: "invalid cursor"},
+		{name: "not json", params: NoteListParams{Cursor: base64.RawURLEncoding.EncodeToString([]byte("not json"))}, wantErr: "invalid cursor"},
+		{name: "missing key", params: NoteListParams{Cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"sort":"created_at"}`))}, wantErr: "invalid cursor"},
+		{
+			name:    "cursor of another sort field",
+			params:  NoteListParams{SortBy: repository.NoteSortCreatedAt, Cursor: encodeNoteCursor(noteCursor{SortBy: repository.NoteSortTitle, Key: key})},
+			wantErr: "cursor does not match the sort order",
+		},
+		{
+			name:    "cursor of the other direction",
+			params:  NoteListParams{SortBy: repository.NoteSortCreatedAt, Cursor: encodeNoteCursor(noteCursor{SortBy: repository.NoteSortCreatedAt, Descending: true, Key: key})},
+			wantErr: "cursor does not match the sort order",
+		},
+		{
+			name:    "cursor of the default sort used with an explicit one",
+			params:  NoteListParams{SortBy: repository.NoteSortUpdatedAt, Cursor: encodeNoteCursor(noteCursor{SortBy: repository.NoteSortCreatedAt, Key: key})},
+			wantErr: "cursor does not match the sort order",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The repository must not be queried with a rejected cursor
+			ctrl := gomock.NewController(t)
+			s := NewNoteService(mocks.NewMockINoteRepository(ctrl), nil, nil, nil, nil, nil, zap.NewNop())
+
+			_, err := s.ListByUserID(1, tc.params)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("ListByUserID error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoteCursorRoundTrip(t *testing.T) {
+	cases := []noteCursor{
+		{SortBy: repository.NoteSortCreatedAt, Descending: true, Key: repository.NoteKey{ID: 5, CreatedAt: time.Date(2026, 10, 1, 11, 0, 0, 123456789, time.UTC)}},
+		{SortBy: repository.NoteSortUpdatedAt, Backward: true, Key: repository.NoteKey{ID: 2, UpdatedAt: time.Date(2026, 10, 1, 10, 0, 0, 0, time.FixedZone("ICT", 7*60*60))}},
+		{SortBy: repository.NoteSortTitle, Key: repository.NoteKey{ID: 7, Title: "บันทึก & \"quotes\" / slashes?"}},
+	}
+
+	for _, want := range cases {
+		t.Run(string(want.SortBy), func(t *testing.T) {
+			encoded := encodeNoteCursor(want)
+			if _, err := base64.RawURLEncoding.DecodeString(encoded); err != nil {
+				t.Fatalf("cursor %q is not URL-safe base64: %v", encoded, err)
+			}
+
+			got, err := decodeNoteCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeNoteCursor failed: %v", err)
+			}
+			if got.SortBy != want.SortBy || got.Descending != want.Descending || got.Backward != want.Backward ||
+				got.Key.ID != want.Key.ID || got.Key.Title != want.Key.Title ||
+				!got.Key.CreatedAt.Equal(want.Key.CreatedAt) || !got.Key.UpdatedAt.Equal(want.Key.UpdatedAt) {
+				t.Errorf("decodeNoteCursor(encodeNoteCursor(%+v)) = %+v", want, *got)
+			}
+		})
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	note := &models.Note{ID: 7, UserID: 1}
+	share := func(permission models.NotePermission) func(*mocks.MockINoteShareRepository) {
+		return func(shareRepo *mocks.MockINoteShareRepository) {
+			shareRepo.EXPECT().Find(uint(7), uint(2)).Return(&models.NoteShare{NoteID: 7, UserID: 2, Permission: permission}, nil)
+		}
+	}
+	shareErr := func(err error) func(*mocks.MockINoteShareRepository) {
+		return func(shareRepo *mocks.MockINoteShareRepository) {
+			shareRepo.EXPECT().Find(uint(7), uint(2)).Return(nil, err)
+		}
+	}
+
+	cases := []struct {
+		name    string
+		userID  uint
+		needed  models.NotePermission
+		setup   func(*mocks.MockINoteShareRepository)
+		wantErr string
+	}{
+		{name: "owner reads", userID: 1, needed: models.NotePermissionRead},
+		{name: "owner edits", userID: 1, needed: models.NotePermissionEdit},
+		{name: "shared read reads", userID: 2, needed: models.NotePermissionRead, setup: share(models.NotePermissionRead)},
+		{name: "shared read edits", userID: 2, needed: models.NotePermissionEdit, setup: share(models.NotePermissionRead), wantErr: "unauthorized access to note"},
+		{name: "shared edit reads", userID: 2, needed: models.NotePermissionRead, setup: share(models.NotePermissionEdit)},
+		{name: "shared edit edits", userID: 2, needed: models.NotePermissionEdit, setup: share(models.NotePermissionEdit)},
+		{name: "not shared", userID: 2, needed: models.NotePermissionRead, setup: shareErr(errors.New("share not found")), wantErr: "unauthorized access to note"},
+		{name: "repository failure", userID: 2, needed: models.NotePermissionRead, setup: shareErr(errors.New("connection refused")), wantErr: "connection refused"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The owner is never looked up in the share repository
+			ctrl := gomock.NewController(t)
+			shareRepo := mocks.NewMockINoteShareRepository(ctrl)
+			if tc.setup != nil {
+				tc.setup(shareRepo)
+			}
+			s := NewNoteService(nil, nil, nil, shareRepo, nil, nil, zap.NewNop()).(*NoteService)
+
+			err := s.checkAccess(note, tc.userID, tc.needed)
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("checkAccess error = %v, want nil", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Errorf("checkAccess error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
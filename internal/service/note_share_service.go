@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Napat/mcpserver-demo/internal/repository"
+	"github.com/Napat/mcpserver-demo/models"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=./note_share_service.go -destination=./mocks/mock_note_share_service.go -package=mocks
+
+// INoteShareService interface for sharing notes between users
+type INoteShareService interface {
+	Share(noteID, ownerID uint, email string, permission models.NotePermission) (*models.NoteShareInfo, error)
+	Unshare(noteID, callerID, userID uint) error
+	ListShares(noteID, ownerID uint) ([]models.NoteShareInfo, error)
+	SharedWithMe(userID uint) ([]models.SharedNote, error)
+}
+
+// NoteShareService struct for handling note sharing business logic
+type NoteShareService struct {
+	shareRepo repository.INoteShareRepository
+	noteRepo  repository.INoteRepository
+	userRepo  repository.IUserRepository
+	logger    *zap.Logger
+}
+
+// NewNoteShareService creates a new instance of NoteShareService
+func NewNoteShareService(shareRepo repository.INoteShareRepository, noteRepo repository.INoteRepository, userRepo repository.IUserRepository, logger *zap.Logger) INoteShareService {
+	return &NoteShareService{
+		shareRepo: shareRepo,
+		noteRepo:  noteRepo,
+		userRepo:  userRepo,
+		logger:    logger,
+	}
+}
+
+// Share grants the user with the given email access to a note, or changes their permission
+// Only the owner can share a note
+func (s *NoteShareService) Share(noteID, ownerID uint, email string, permission models.NotePermission) (*models.NoteShareInfo, error) {
+	if !permission.Valid() {
+		return nil, errors.New("invalid permission")
+	}
+
+	if _, err := s.ownedNote(noteID, ownerID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, errors.New("user not found")
+	}
+	if uint(user.ID) == ownerID {
+		return nil, errors.New("cannot share a note with its owner")
+	}
+
+	share := &models.NoteShare{
+		NoteID:     noteID,
+		UserID:     uint(user.ID),
+		Permission: permission,
+	}
+	if err := s.shareRepo.Upsert(share); err != nil {
+		return nil, err
+	}
+
+	info := shareInfo(share, user)
+	return &info, nil
+}
+
+// Unshare removes a user's access to a note
+// The owner can remove anyone, and a user the note is shared with can remove themselves
+func (s *NoteShareService) Unshare(noteID, callerID, userID uint) error {
+	if callerID != userID {
+		if _, err := s.ownedNote(noteID, callerID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.shareRepo.Find(noteID, userID); err != nil {
+		return err
+	}
+
+	return s.shareRepo.Delete(noteID, userID)
+}
+
+// ListShares retrieves everyone a note is shared with; only the owner can list them
+func (s *NoteShareService) ListShares(noteID, ownerID uint) ([]models.NoteShareInfo, error) {
+	if _, err := s.ownedNote(noteID, ownerID); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.FindByNoteID(noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]models.NoteShareInfo, 0, len(shares))
+	for i := range shares {
+		infos = append(infos, shareInfo(&shares[i], shares[i].User))
+	}
+	return infos, nil
+}
+
+// SharedWithMe retrieves the notes other users shared with a user, most recently shared first
+func (s *NoteShareService) SharedWithMe(userID uint) ([]models.SharedNote, error) {
+	shares, err := s.shareRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]models.SharedNote, 0, len(shares))
+	for _, share := range shares {
+		if share.Note == nil {
+			continue
+		}
+
+		note := *share.Note
+		owner := note.User.Email
+		// Only the owner's email is exposed, not the rest of their account
+		note.User = models.User{}
+
+		notes = append(notes, models.SharedNote{
+			Note:       note,
+			OwnerEmail: owner,
+			Permission: share.Permission,
+			SharedAt:   share.UpdatedAt,
+		})
+	}
+	return notes, nil
+}
+
+// ownedNote retrieves a note and checks that userID owns it
+func (s *NoteShareService) ownedNote(noteID, userID uint) (*models.Note, error) {
+	note, err := s.noteRepo.FindByID(noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.UserID != userID {
+		return nil, errors.New("unauthorized access to note")
+	}
+
+	return note, nil
+}
+
+// shareInfo describes a share together with the user it was granted to
+func shareInfo(share *models.NoteShare, user *models.User) models.NoteShareInfo {
+	info := models.NoteShareInfo{
+		UserID:     share.UserID,
+		Permission: share.Permission,
+		SharedAt:   share.UpdatedAt,
+	}
+	if user != nil {
+		info.Email = user.Email
+		info.FirstName = user.FirstName
+		info.LastName = user.LastName
+	}
+	return info
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Napat/mcpserver-demo/internal/repository/mocks"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// shareMocks holds the repositories of a NoteShareService under test
+type shareMocks struct {
+	shares *mocks.MockINoteShareRepository
+	notes  *mocks.MockINoteRepository
+	users  *mocks.MockIUserRepository
+}
+
+// newShareService creates a NoteShareService whose note 7 is owned by user 1
+func newShareService(t *testing.T) (INoteShareService, shareMocks) {
+	ctrl := gomock.NewController(t)
+	m := shareMocks{
+		shares: mocks.NewMockINoteShareRepository(ctrl),
+		notes:  mocks.NewMockINoteRepository(ctrl),
+		users:  mocks.NewMockIUserRepository(ctrl),
+	}
+	m.notes.EXPECT().FindByID(uint(7)).Return(&models.Note{ID: 7, UserID: 1}, nil).AnyTimes()
+	return NewNoteShareService(m.shares, m.notes, m.users, zap.NewNop()), m
+}
+
+func TestUnshare(t *testing.T) {
+	cases := []struct {
+		name     string
+		callerID uint
+		userID   uint
+		setup    func(m shareMocks)
+		wantErr  string
+	}{
+		{
+			name:     "owner removes a share",
+			callerID: 1,
+			userID:   2,
+			setup: func(m shareMocks) {
+				m.shares.EXPECT().Find(uint(7), uint(2)).Return(&models.NoteShare{NoteID: 7, UserID: 2}, nil)
+				m.shares.EXPECT().Delete(uint(7), uint(2)).Return(nil)
+			},
+		},
+		{
+			name:     "owner removes a missing share",
+			callerID: 1,
+			userID:   2,
+			setup: func(m shareMocks) {
+				m.shares.EXPECT().Find(uint(7), uint(2)).Return(nil, errors.New("share not found"))
+			},
+			wantErr: "share not found",
+		},
+		{
+			name:     "shared user removes themselves",
+			callerID: 2,
+			userID:   2,
+			setup: func(m shareMocks) {
+				m.shares.EXPECT().Find(uint(7), uint(2)).Return(&models.NoteShare{NoteID: 7, UserID: 2, Permission: models.NotePermissionRead}, nil)
+				m.shares.EXPECT().Delete(uint(7), uint(2)).Return(nil)
+			},
+		},
+		{
+			name:     "user without a share removes themselves",
+			callerID: 2,
+			userID:   2,
+			setup: func(m shareMocks) {
+				m.shares.EXPECT().Find(uint(7), uint(2)).Return(nil, errors.New("share not found"))
+			},
+			wantErr: "share not found",
+		},
+		{
+			name:     "shared user removes someone else",
+			callerID: 2,
+			userID:   3,
+			wantErr:  "unauthorized access to note",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, m := newShareService(t)
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			err := s.Unshare(7, tc.callerID, tc.userID)
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("Unshare error = %v, want nil", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Errorf("Unshare error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestListShares(t *testing.T) {
+	t.Run("owner", func(t *testing.T) {
+		s, m := newShareService(t)
+		m.shares.EXPECT().FindByNoteID(uint(7)).Return([]models.NoteShare{
+			{NoteID: 7, UserID: 2, Permission: models.NotePermissionEdit, User: &models.User{ID: 2, Email: "bob@example.com", FirstName: "Bob"}},
+		}, nil)
+
+		infos, err := s.ListShares(7, 1)
+		if err != nil {
+			t.Fatalf("ListShares failed: %v", err)
+		}
+		want := []models.NoteShareInfo{{UserID: 2, Email: "bob@example.com", FirstName: "Bob", Permission: models.NotePermissionEdit}}
+		if !reflect.DeepEqual(infos, want) {
+			t.Errorf("ListShares = %+v, want %+v", infos, want)
+		}
+	})
+
+	t.Run("shared user", func(t *testing.T) {
+		// Users the note is shared with cannot see who else has access
+		s, _ := newShareService(t)
+		if _, err := s.ListShares(7, 2); err == nil || err.Error() != "unauthorized access to note" {
+			t.Errorf("ListShares error = %v, want %q", err, "unauthorized access to note")
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetLength is the number of characters of content shown in a search snippet
+	snippetLength = 160
+	// snippetLead is the number of characters shown before the first match
+	snippetLead = 40
+)
+
+// searchTerms splits a search query into distinct lower-case words
+// Thai text is kept as written because it has no spaces between words; the repository matches it as a substring
+func searchTerms(query string) []string {
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+		if len(terms) == maxNoteSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// highlightSnippet returns an HTML-escaped excerpt of content around the first matched term,
+// with every occurrence of the terms wrapped in <mark> tags
+// It works on runes so that Thai characters are never split
+func highlightSnippet(content string, terms []string) string {
+	runes := []rune(content)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	needles := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		needles = append(needles, []rune(term))
+	}
+
+	start := 0
+	if first := firstMatch(lower, needles, 0); first > snippetLead {
+		start = first - snippetLead
+	}
+	end := start + snippetLength
+	if end > len(runes) {
+		end = len(runes)
+		if start = end - snippetLength; start < 0 {
+			start = 0
+		}
+	}
+	// Keep Thai vowels and tone marks with the consonant they belong to
+	for start > 0 && unicode.Is(unicode.Mn, runes[start]) {
+		start--
+	}
+	for end < len(runes) && unicode.Is(unicode.Mn, runes[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		if n := matchLength(lower, i, needles); n > 0 {
+			n = min(n, end-i)
+			b.WriteString("<mark>" + html.EscapeString(string(runes[i:i+n])) + "</mark>")
+			i += n
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// firstMatch returns the position of the first term in text at or after from, or -1
+func firstMatch(text []rune, needles [][]rune, from int) int {
+	for i := from; i < len(text); i++ {
+		if matchLength(text, i, needles) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchLength returns the length of the longest term that starts at position i of text, or 0
+func matchLength(text []rune, i int, needles [][]rune) int {
+	longest := 0
+	for _, needle := range needles {
+		if len(needle) > longest && hasRunePrefix(text[i:], needle) {
+			longest = len(needle)
+		}
+	}
+	return longest
+}
+
+// hasRunePrefix reports whether text starts with prefix
+func hasRunePrefix(text, prefix []rune) bool {
+	if len(prefix) == 0 || len(prefix) > len(text) {
+		return false
+	}
+	for i, r := range prefix {
+		if text[i] != r {
+			return false
+		}
+	}
+	return true
+}
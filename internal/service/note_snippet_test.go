@@ -0,0 +1,66 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "lower-cased and deduplicated", query: "  Milk milk EGGS ", want: []string{"milk", "eggs"}},
+		{name: "thai kept as written", query: "ซื้อนม ไข่", want: []string{"ซื้อนม", "ไข่"}},
+		{name: "empty", query: "   ", want: []string{}},
+		{name: "at most maxNoteSearchTerms", query: "a b c d e f g h i j k l", want: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := searchTerms(tc.query); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tc.query, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	thai := strings.Repeat("ที่", 30) + "นม" + strings.Repeat("ที่", 60)
+
+	cases := []struct {
+		name    string
+		content string
+		terms   []string
+		want    string
+	}{
+		{name: "no match", content: "plain text", terms: []string{"milk"}, want: "plain text"},
+		{name: "case-insensitive", content: "Buy Milk and MILK", terms: []string{"milk"}, want: "Buy <mark>Milk</mark> and <mark>MILK</mark>"},
+		{name: "html escaped", content: `<b>milk</b> & "eggs"`, terms: []string{"milk"}, want: "&lt;b&gt;<mark>milk</mark>&lt;/b&gt; &amp; &#34;eggs&#34;"},
+		{name: "longest term wins", content: "milkshake", terms: []string{"milk", "milkshake"}, want: "<mark>milkshake</mark>"},
+		{name: "several terms", content: "milk and eggs", terms: []string{"eggs", "milk"}, want: "<mark>milk</mark> and <mark>eggs</mark>"},
+		{name: "thai substring", content: "ซื้อนมและไข่", terms: []string{"นม"}, want: "ซื้อ<mark>นม</mark>และไข่"},
+		{
+			name:    "window before the first match",
+			content: strings.Repeat("x", 100) + "needle" + strings.Repeat("y", 100),
+			terms:   []string{"needle"},
+			want:    "…" + strings.Repeat("x", 54) + "<mark>needle</mark>" + strings.Repeat("y", 100),
+		},
+		{
+			name:    "window keeps thai marks with their consonant",
+			content: thai,
+			terms:   []string{"นม"},
+			want:    "…" + strings.Repeat("ที่", 14) + "<mark>นม</mark>" + strings.Repeat("ที่", 40) + "…",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := highlightSnippet(tc.content, tc.terms); got != tc.want {
+				t.Errorf("highlightSnippet(%q, %q)\n got %q\nwant %q", tc.content, tc.terms, got, tc.want)
+			}
+		})
+	}
+}
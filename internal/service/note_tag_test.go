@@ -0,0 +1,189 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Napat/mcpserver-demo/internal/repository/mocks"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestNormalizeTagNames(t *testing.T) {
+	cases := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "trimmed and lower-cased", names: []string{" Work ", "URGENT"}, want: []string{"work", "urgent"}},
+		{name: "deduplicated in order", names: []string{"work", "Work", "home", " WORK"}, want: []string{"work", "home"}},
+		{name: "thai kept as written", names: []string{"งาน", " งาน "}, want: []string{"งาน"}},
+		{name: "none", names: nil, want: []string{}},
+		{name: "blank", names: []string{"work", "  "}, wantErr: true},
+		{name: "longest name", names: []string{strings.Repeat("ก", maxTagNameLength)}, want: []string{strings.Repeat("ก", maxTagNameLength)}},
+		{name: "too long", names: []string{strings.Repeat("ก", maxTagNameLength+1)}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := normalizeTagNames(tc.names)
+			if tc.wantErr {
+				if err == nil || err.Error() != "invalid tag name" {
+					t.Errorf("normalizeTagNames(%q) error = %v, want invalid tag name", tc.names, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("normalizeTagNames(%q) = %q, %v, want %q", tc.names, got, err, tc.want)
+			}
+		})
+	}
+}
+
+// tagMocks holds the repositories of a NoteService whose tag and notebook operations are under test
+type tagMocks struct {
+	notes     *mocks.MockINoteRepository
+	tags      *mocks.MockITagRepository
+	notebooks *mocks.MockINotebookRepository
+}
+
+func TestNoteTagsAndNotebooks(t *testing.T) {
+	owned := &models.Note{ID: 7, UserID: 1}
+	foreign := &models.Note{ID: 8, UserID: 2}
+	ownTag := &models.Tag{ID: 3, UserID: 1, Name: "work"}
+	foreignTag := &models.Tag{ID: 4, UserID: 2, Name: "work"}
+
+	cases := []struct {
+		name    string
+		call    func(s INoteService) error
+		setup   func(m tagMocks)
+		wantErr string
+	}{
+		{
+			name: "add normalized tags",
+			call: func(s INoteService) error { _, err := s.AddTags(7, 1, []string{" Work", "work", "URGENT"}); return err },
+			setup: func(m tagMocks) {
+				tags := []models.Tag{*ownTag, {ID: 5, UserID: 1, Name: "urgent"}}
+				m.tags.EXPECT().FindOrCreate(uint(1), []string{"work", "urgent"}).Return(tags, nil)
+				m.notes.EXPECT().AddTags(uint(7), tags).Return(nil)
+			},
+		},
+		{
+			name:    "add no tags",
+			call:    func(s INoteService) error { _, err := s.AddTags(7, 1, nil); return err },
+			wantErr: "invalid tag name",
+		},
+		{
+			name: "add too many tags",
+			call: func(s INoteService) error {
+				names := []string{}
+				for i := 0; i <= maxTagsPerRequest; i++ {
+					names = append(names, fmt.Sprintf("tag%d", i))
+				}
+				_, err := s.AddTags(7, 1, names)
+				return err
+			},
+			wantErr: "invalid tag name",
+		},
+		{
+			name:    "add tags to another user's note",
+			call:    func(s INoteService) error { _, err := s.AddTags(8, 1, []string{"work"}); return err },
+			wantErr: "unauthorized access to note",
+		},
+		{
+			name: "remove own tag",
+			call: func(s INoteService) error { _, err := s.RemoveTag(7, 1, 3); return err },
+			setup: func(m tagMocks) {
+				m.tags.EXPECT().FindByID(uint(3)).Return(ownTag, nil)
+				m.notes.EXPECT().RemoveTag(uint(7), uint(3)).Return(nil)
+			},
+		},
+		{
+			name:    "remove a tag from another user's note",
+			call:    func(s INoteService) error { _, err := s.RemoveTag(8, 1, 3); return err },
+			wantErr: "unauthorized access to note",
+		},
+		{
+			name: "remove another user's tag",
+			call: func(s INoteService) error { _, err := s.RemoveTag(7, 1, 4); return err },
+			setup: func(m tagMocks) {
+				m.tags.EXPECT().FindByID(uint(4)).Return(foreignTag, nil)
+			},
+			wantErr: "unauthorized access to tag",
+		},
+		{
+			name: "delete own tag",
+			call: func(s INoteService) error { return s.DeleteTag(3, 1) },
+			setup: func(m tagMocks) {
+				m.tags.EXPECT().FindByID(uint(3)).Return(ownTag, nil)
+				m.tags.EXPECT().Delete(uint(3)).Return(nil)
+			},
+		},
+		{
+			name: "delete another user's tag",
+			call: func(s INoteService) error { return s.DeleteTag(4, 1) },
+			setup: func(m tagMocks) {
+				m.tags.EXPECT().FindByID(uint(4)).Return(foreignTag, nil)
+			},
+			wantErr: "unauthorized access to tag",
+		},
+		{
+			name: "file in own notebook",
+			call: func(s INoteService) error { _, err := s.MoveToNotebook(7, 1, uintPtr(1)); return err },
+			setup: func(m tagMocks) {
+				m.notebooks.EXPECT().FindByID(uint(1)).DoAndReturn(findNotebook)
+				m.notes.EXPECT().UpdateNotebook(uint(7), uintPtr(1)).Return(nil)
+			},
+		},
+		{
+			name: "take out of its notebook",
+			call: func(s INoteService) error { _, err := s.MoveToNotebook(7, 1, nil); return err },
+			setup: func(m tagMocks) {
+				m.notes.EXPECT().UpdateNotebook(uint(7), nil).Return(nil)
+			},
+		},
+		{
+			name: "file in another user's notebook",
+			call: func(s INoteService) error { _, err := s.MoveToNotebook(7, 1, uintPtr(3)); return err },
+			setup: func(m tagMocks) {
+				m.notebooks.EXPECT().FindByID(uint(3)).DoAndReturn(findNotebook)
+			},
+			wantErr: "unauthorized access to notebook",
+		},
+		{
+			name:    "file another user's note",
+			call:    func(s INoteService) error { _, err := s.MoveToNotebook(8, 1, uintPtr(1)); return err },
+			wantErr: "unauthorized access to note",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Only the owner's notes and tags are changed, so writes are only expected when a case sets them up
+			ctrl := gomock.NewController(t)
+			m := tagMocks{
+				notes:     mocks.NewMockINoteRepository(ctrl),
+				tags:      mocks.NewMockITagRepository(ctrl),
+				notebooks: mocks.NewMockINotebookRepository(ctrl),
+			}
+			m.notes.EXPECT().FindByID(uint(7)).Return(owned, nil).AnyTimes()
+			m.notes.EXPECT().FindByID(uint(8)).Return(foreign, nil).AnyTimes()
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+			s := NewNoteService(m.notes, m.tags, m.notebooks, nil, nil, nil, zap.NewNop())
+
+			err := tc.call(s)
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("error = %v, want nil", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Errorf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Napat/mcpserver-demo/internal/repository"
+	"github.com/Napat/mcpserver-demo/models"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=./notebook_service.go -destination=./mocks/mock_notebook_service.go -package=mocks
+
+// maxNotebookNameLength is the longest notebook name in characters
+const maxNotebookNameLength = 100
+
+// INotebookService interface for managing notebook business logic
+type INotebookService interface {
+	Create(notebook *models.Notebook) error
+	GetByID(id, userID uint) (*models.Notebook, error)
+	GetAllByUserID(userID uint) ([]models.Notebook, error)
+	Update(notebook *models.Notebook, userID uint) error
+	Delete(id, userID uint) error
+}
+
+// NotebookService struct for handling notebook business logic
+type NotebookService struct {
+	notebookRepo repository.INotebookRepository
+	logger       *zap.Logger
+}
+
+// NewNotebookService creates a new instance of NotebookService
+func NewNotebookService(notebookRepo repository.INotebookRepository, logger *zap.Logger) INotebookService {
+	return &NotebookService{
+		notebookRepo: notebookRepo,
+		logger:       logger,
+	}
+}
+
+// Create creates a notebook, optionally inside another notebook of the same user
+func (s *NotebookService) Create(notebook *models.Notebook) error {
+	name, err := normalizeNotebookName(notebook.Name)
+	if err != nil {
+		return err
+	}
+	notebook.Name = name
+
+	if notebook.ParentID != nil {
+		if _, err := s.GetByID(*notebook.ParentID, notebook.UserID); err != nil {
+			return err
+		}
+	}
+
+	return s.notebookRepo.Create(notebook)
+}
+
+// GetByID retrieves a notebook by ID and checks access permissions
+func (s *NotebookService) GetByID(id, userID uint) (*models.Notebook, error) {
+	notebook, err := s.notebookRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if notebook.UserID != userID {
+		return nil, errors.New("unauthorized access to notebook")
+	}
+
+	return notebook, nil
+}
+
+// GetAllByUserID retrieves all notebooks of a user; ParentID describes the hierarchy
+func (s *NotebookService) GetAllByUserID(userID uint) ([]models.Notebook, error) {
+	return s.notebookRepo.FindByUserID(userID)
+}
+
+// Update renames a notebook or moves it under another parent (nil moves it to the top level)
+func (s *NotebookService) Update(notebook *models.Notebook, userID uint) error {
+	existing, err := s.GetByID(notebook.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	name, err := normalizeNotebookName(notebook.Name)
+	if err != nil {
+		return err
+	}
+
+	if notebook.ParentID != nil {
+		if err := s.checkParent(notebook.ID, *notebook.ParentID, userID); err != nil {
+			return err
+		}
+	}
+
+	existing.Name = name
+	existing.ParentID = notebook.ParentID
+	if err := s.notebookRepo.Update(existing); err != nil {
+		return err
+	}
+
+	*notebook = *existing
+	return nil
+}
+
+// Delete removes a notebook that has no child notebooks; the notes in it are no longer filed in any notebook
+func (s *NotebookService) Delete(id, userID uint) error {
+	if _, err := s.GetByID(id, userID); err != nil {
+		return err
+	}
+
+	children, err := s.notebookRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("notebook has child notebooks")
+	}
+
+	return s.notebookRepo.Delete(id)
+}
+
+// checkParent makes sure parentID belongs to the user and is not the notebook itself or one of its descendants
+func (s *NotebookService) checkParent(id, parentID, userID uint) error {
+	for current := &parentID; current != nil; {
+		if *current == id {
+			return errors.New("notebook cannot be moved into itself")
+		}
+
+		parent, err := s.GetByID(*current, userID)
+		if err != nil {
+			return err
+		}
+		current = parent.ParentID
+	}
+	return nil
+}
+
+// normalizeNotebookName trims a notebook name and checks its length
+func normalizeNotebookName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNotebookNameLength {
+		return "", errors.New("invalid notebook name")
+	}
+	return name, nil
+}
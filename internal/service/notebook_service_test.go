@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Napat/mcpserver-demo/internal/repository/mocks"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// notebookTree has two nested notebooks of user 1 and one notebook of user 2
+var notebookTree = map[uint]models.Notebook{
+	1: {ID: 1, UserID: 1, Name: "Work"},
+	2: {ID: 2, UserID: 1, ParentID: uintPtr(1), Name: "Projects"},
+	3: {ID: 3, UserID: 2, Name: "Private"},
+}
+
+// uintPtr returns a pointer to v
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+// findNotebook mimics NotebookRepository.FindByID on notebookTree
+func findNotebook(id uint) (*models.Notebook, error) {
+	notebook, ok := notebookTree[id]
+	if !ok {
+		return nil, errors.New("notebook not found")
+	}
+	return &notebook, nil
+}
+
+func TestNotebookService(t *testing.T) {
+	cases := []struct {
+		name    string
+		call    func(s INotebookService) error
+		setup   func(repo *mocks.MockINotebookRepository)
+		wantErr string
+	}{
+		{
+			name: "get own notebook",
+			call: func(s INotebookService) error { _, err := s.GetByID(2, 1); return err },
+		},
+		{
+			name:    "get another user's notebook",
+			call:    func(s INotebookService) error { _, err := s.GetByID(3, 1); return err },
+			wantErr: "unauthorized access to notebook",
+		},
+		{
+			name: "create with a trimmed name",
+			call: func(s INotebookService) error {
+				return s.Create(&models.Notebook{UserID: 1, ParentID: uintPtr(1), Name: "  Ideas "})
+			},
+			setup: func(repo *mocks.MockINotebookRepository) {
+				repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(notebook *models.Notebook) error {
+					if notebook.Name != "Ideas" {
+						t.Errorf("created name = %q, want %q", notebook.Name, "Ideas")
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:    "create with a blank name",
+			call:    func(s INotebookService) error { return s.Create(&models.Notebook{UserID: 1, Name: "  "}) },
+			wantErr: "invalid notebook name",
+		},
+		{
+			name: "create with a name that is too long",
+			call: func(s INotebookService) error {
+				return s.Create(&models.Notebook{UserID: 1, Name: strings.Repeat("ก", 101)})
+			},
+			wantErr: "invalid notebook name",
+		},
+		{
+			name: "create inside another user's notebook",
+			call: func(s INotebookService) error {
+				return s.Create(&models.Notebook{UserID: 1, ParentID: uintPtr(3), Name: "Ideas"})
+			},
+			wantErr: "unauthorized access to notebook",
+		},
+		{
+			name: "move to the top level",
+			call: func(s INotebookService) error { return s.Update(&models.Notebook{ID: 2, Name: "Projects"}, 1) },
+			setup: func(repo *mocks.MockINotebookRepository) {
+				repo.EXPECT().Update(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "rename another user's notebook",
+			call:    func(s INotebookService) error { return s.Update(&models.Notebook{ID: 3, Name: "Mine"}, 1) },
+			wantErr: "unauthorized access to notebook",
+		},
+		{
+			name: "move under another user's notebook",
+			call: func(s INotebookService) error {
+				return s.Update(&models.Notebook{ID: 2, ParentID: uintPtr(3), Name: "Projects"}, 1)
+			},
+			wantErr: "unauthorized access to notebook",
+		},
+		{
+			name: "move into itself",
+			call: func(s INotebookService) error {
+				return s.Update(&models.Notebook{ID: 1, ParentID: uintPtr(1), Name: "Work"}, 1)
+			},
+			wantErr: "notebook cannot be moved into itself",
+		},
+		{
+			name: "move into a descendant",
+			call: func(s INotebookService) error {
+				return s.Update(&models.Notebook{ID: 1, ParentID: uintPtr(2), Name: "Work"}, 1)
+			},
+			wantErr: "notebook cannot be moved into itself",
+		},
+		{
+			name: "delete a notebook without children",
+			call: func(s INotebookService) error { return s.Delete(2, 1) },
+			setup: func(repo *mocks.MockINotebookRepository) {
+				repo.EXPECT().CountChildren(uint(2)).Return(int64(0), nil)
+				repo.EXPECT().Delete(uint(2)).Return(nil)
+			},
+		},
+		{
+			name: "delete a notebook with children",
+			call: func(s INotebookService) error { return s.Delete(1, 1) },
+			setup: func(repo *mocks.MockINotebookRepository) {
+				repo.EXPECT().CountChildren(uint(1)).Return(int64(1), nil)
+			},
+			wantErr: "notebook has child notebooks",
+		},
+		{
+			name:    "delete another user's notebook",
+			call:    func(s INotebookService) error { return s.Delete(3, 1) },
+			wantErr: "unauthorized access to notebook",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Writes are only expected when a case sets them up
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockINotebookRepository(ctrl)
+			repo.EXPECT().FindByID(gomock.Any()).DoAndReturn(findNotebook).AnyTimes()
+			if tc.setup != nil {
+				tc.setup(repo)
+			}
+
+			err := tc.call(NewNotebookService(repo, zap.NewNop()))
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("error = %v, want nil", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Errorf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
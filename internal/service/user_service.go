@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"mime/multipart"
 	"time"
 
@@ -21,8 +22,20 @@ type IUserService interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetLoginHistory(userID uint, limit int) ([]models.LoginHistory, error)
 	RecordLogin(userID uint, ipAddress, userAgent string) error
+
+	// Admin operations, actorID and actorRole identify the admin performing the change
+	ListUsers() ([]models.User, error)
+	DeactivateUser(id, actorID uint, actorRole models.UserRole) (*models.User, error)
+	ChangeRole(id uint, role models.UserRole, actorID uint, actorRole models.UserRole) (*models.User, error)
 }
 
+const (
+	// allRoles is the union of every defined role bit
+	allRoles = models.RoleUser | models.RoleStaff | models.RoleManager | models.RoleAdmin | models.RoleSuperAdmin
+	// adminRoles are the roles that give access to the admin operations
+	adminRoles = models.RoleAdmin | models.RoleSuperAdmin
+)
+
 // UserService struct for handling user business logic
 type UserService struct {
 	userRepo repository.IUserRepository
@@ -94,3 +107,67 @@ func (s *UserService) RecordLogin(userID uint, ipAddress, userAgent string) erro
 	}
 	return s.userRepo.RecordLogin(history)
 }
+
+// ListUsers retrieves all users, including inactive ones
+func (s *UserService) ListUsers() ([]models.User, error) {
+	return s.userRepo.FindAll()
+}
+
+// DeactivateUser deactivates a user so they can no longer log in
+// Admins cannot deactivate their own account and only super admins may deactivate a super admin
+func (s *UserService) DeactivateUser(id, actorID uint, actorRole models.UserRole) (*models.User, error) {
+	if id == actorID {
+		return nil, errors.New("cannot deactivate your own account")
+	}
+
+	if !actorRole.HasRole(models.RoleSuperAdmin) {
+		existing, err := s.userRepo.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if existing.IsSuperAdmin() {
+			return nil, errors.New("only super admins can manage super admins")
+		}
+	}
+
+	return s.userRepo.UpdateActive(id, false)
+}
+
+// ChangeRole replaces the roles of a user
+// Only super admins may grant the super admin role or change the roles of a super admin,
+// admins cannot remove their own admin roles and the last active super admin cannot lose the role
+func (s *UserService) ChangeRole(id uint, role models.UserRole, actorID uint, actorRole models.UserRole) (*models.User, error) {
+	if role == 0 || role&^allRoles != 0 {
+		return nil, errors.New("invalid role")
+	}
+
+	if id == actorID && actorRole&adminRoles&^role != 0 {
+		return nil, errors.New("cannot remove your own admin roles")
+	}
+
+	if !actorRole.HasRole(models.RoleSuperAdmin) && role.HasRole(models.RoleSuperAdmin) {
+		return nil, errors.New("only super admins can manage super admins")
+	}
+
+	existing, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.IsSuperAdmin() {
+		if !actorRole.HasRole(models.RoleSuperAdmin) {
+			return nil, errors.New("only super admins can manage super admins")
+		}
+		if !role.HasRole(models.RoleSuperAdmin) {
+			count, err := s.userRepo.CountActiveWithRole(models.RoleSuperAdmin)
+			if err != nil {
+				return nil, err
+			}
+			if existing.Active && count <= 1 {
+				return nil, errors.New("cannot remove the last super admin")
+			}
+		}
+	}
+
+	return s.userRepo.UpdateRole(id, role)
+}
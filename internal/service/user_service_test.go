@@ -0,0 +1,204 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Napat/mcpserver-demo/internal/repository/mocks"
+	"github.com/Napat/mcpserver-demo/models"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// Users seen by the admin operations under test
+var (
+	adminUser      = &models.User{ID: 1, Role: models.RoleUser | models.RoleAdmin, Active: true}
+	superAdminUser = &models.User{ID: 2, Role: models.RoleUser | models.RoleSuperAdmin, Active: true}
+	regularUser    = &models.User{ID: 3, Role: models.RoleUser, Active: true}
+)
+
+// newUserService creates a UserService backed by a mock user repository
+func newUserService(t *testing.T) (IUserService, *mocks.MockIUserRepository) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockIUserRepository(ctrl)
+	return NewUserService(users, zap.NewNop()), users
+}
+
+func TestDeactivateUser(t *testing.T) {
+	cases := []struct {
+		name    string
+		id      uint
+		actor   *models.User
+		setup   func(users *mocks.MockIUserRepository)
+		wantErr string
+	}{
+		{
+			name:  "admin deactivates a user",
+			id:    3,
+			actor: adminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(3)).Return(regularUser, nil)
+				users.EXPECT().UpdateActive(uint(3), false).Return(&models.User{ID: 3}, nil)
+			},
+		},
+		{
+			name:  "admin deactivates a super admin",
+			id:    2,
+			actor: adminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(2)).Return(superAdminUser, nil)
+			},
+			wantErr: "only super admins can manage super admins",
+		},
+		{
+			name:  "super admin deactivates another super admin",
+			id:    4,
+			actor: superAdminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().UpdateActive(uint(4), false).Return(&models.User{ID: 4}, nil)
+			},
+		},
+		{
+			name:    "admin deactivates themselves",
+			id:      1,
+			actor:   adminUser,
+			wantErr: "cannot deactivate your own account",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, users := newUserService(t)
+			if tc.setup != nil {
+				tc.setup(users)
+			}
+
+			_, err := s.DeactivateUser(tc.id, uint(tc.actor.ID), tc.actor.Role)
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("DeactivateUser error = %v, want nil", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Errorf("DeactivateUser error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestChangeRole(t *testing.T) {
+	cases := []struct {
+		name    string
+		id      uint
+		role    models.UserRole
+		actor   *models.User
+		setup   func(users *mocks.MockIUserRepository)
+		wantErr string
+	}{
+		{
+			name:  "admin promotes a user to manager",
+			id:    3,
+			role:  models.RoleUser | models.RoleManager,
+			actor: adminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(3)).Return(regularUser, nil)
+				users.EXPECT().UpdateRole(uint(3), models.RoleUser|models.RoleManager).Return(&models.User{ID: 3}, nil)
+			},
+		},
+		{
+			name:    "invalid role",
+			id:      3,
+			role:    1 << 7,
+			actor:   adminUser,
+			wantErr: "invalid role",
+		},
+		{
+			name:    "admin grants super admin",
+			id:      3,
+			role:    models.RoleSuperAdmin,
+			actor:   adminUser,
+			wantErr: "only super admins can manage super admins",
+		},
+		{
+			name:  "admin demotes a super admin",
+			id:    2,
+			role:  models.RoleUser,
+			actor: adminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(2)).Return(superAdminUser, nil)
+			},
+			wantErr: "only super admins can manage super admins",
+		},
+		{
+			name:    "admin demotes themselves",
+			id:      1,
+			role:    models.RoleUser,
+			actor:   adminUser,
+			wantErr: "cannot remove your own admin roles",
+		},
+		{
+			name:    "super admin demotes themselves to admin",
+			id:      2,
+			role:    models.RoleUser | models.RoleAdmin,
+			actor:   superAdminUser,
+			wantErr: "cannot remove your own admin roles",
+		},
+		{
+			name:  "admin adds a role to themselves",
+			id:    1,
+			role:  models.RoleUser | models.RoleStaff | models.RoleAdmin,
+			actor: adminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(1)).Return(adminUser, nil)
+				users.EXPECT().UpdateRole(uint(1), models.RoleUser|models.RoleStaff|models.RoleAdmin).Return(&models.User{ID: 1}, nil)
+			},
+		},
+		{
+			name:  "super admin demotes the last super admin",
+			id:    4,
+			role:  models.RoleUser,
+			actor: superAdminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(4)).Return(&models.User{ID: 4, Role: models.RoleSuperAdmin, Active: true}, nil)
+				users.EXPECT().CountActiveWithRole(models.RoleSuperAdmin).Return(int64(1), nil)
+			},
+			wantErr: "cannot remove the last super admin",
+		},
+		{
+			name:  "super admin demotes one of several super admins",
+			id:    4,
+			role:  models.RoleUser,
+			actor: superAdminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(4)).Return(&models.User{ID: 4, Role: models.RoleSuperAdmin, Active: true}, nil)
+				users.EXPECT().CountActiveWithRole(models.RoleSuperAdmin).Return(int64(2), nil)
+				users.EXPECT().UpdateRole(uint(4), models.RoleUser).Return(&models.User{ID: 4}, nil)
+			},
+		},
+		{
+			name:  "super admin demotes an inactive super admin",
+			id:    4,
+			role:  models.RoleUser,
+			actor: superAdminUser,
+			setup: func(users *mocks.MockIUserRepository) {
+				users.EXPECT().FindByID(uint(4)).Return(&models.User{ID: 4, Role: models.RoleSuperAdmin}, nil)
+				users.EXPECT().CountActiveWithRole(models.RoleSuperAdmin).Return(int64(1), nil)
+				users.EXPECT().UpdateRole(uint(4), models.RoleUser).Return(&models.User{ID: 4}, nil)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, users := newUserService(t)
+			if tc.setup != nil {
+				tc.setup(users)
+			}
+
+			_, err := s.ChangeRole(tc.id, tc.role, uint(tc.actor.ID), tc.actor.Role)
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("ChangeRole error = %v, want nil", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Errorf("ChangeRole error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditOutcome is the result of an audited action
+type AuditOutcome string
+
+const (
+	// AuditOutcomeSuccess means the tool call returned a result
+	AuditOutcomeSuccess AuditOutcome = "success"
+	// AuditOutcomeError means the tool call failed or returned an error result
+	AuditOutcomeError AuditOutcome = "error"
+)
+
+// AuditLog is a model for storing the MCP tool calls made on behalf of users
+type AuditLog struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	Tool       string       `gorm:"size:100;not null;index:idx_audit_logs_tool" json:"tool"`
+	SessionID  string       `gorm:"size:255;index:idx_audit_logs_session_id" json:"session_id,omitempty"`
+	Client     string       `gorm:"size:255" json:"client,omitempty"`
+	UserID     *uint        `gorm:"index:idx_audit_logs_user_id" json:"user_id,omitempty"`
+	Backend    string       `gorm:"size:100" json:"backend,omitempty"`
+	Arguments  string       `gorm:"type:jsonb" json:"arguments,omitempty"`
+	Outcome    AuditOutcome `gorm:"size:20;not null" json:"outcome"`
+	ErrorCode  string       `gorm:"size:50" json:"error_code,omitempty"`
+	Error      string       `gorm:"type:text" json:"error,omitempty"`
+	DurationMs int64        `gorm:"not null" json:"duration_ms"`
+	CreatedAt  time.Time    `gorm:"default:CURRENT_TIMESTAMP;index:idx_audit_logs_created_at" json:"created_at"`
+}
+
+// TableName defines the table name
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate runs before creating data
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	return nil
+}
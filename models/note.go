@@ -15,6 +15,30 @@ type Note struct {
 	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;index:idx_notes_created_at" json:"created_at"`
 	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;index:idx_notes_updated_at" json:"updated_at"`
+	// NotebookID is the notebook that holds the note, or nil when the note is not filed
+	NotebookID *uint     `gorm:"index:idx_notes_notebook_id" json:"notebook_id"`
+	Notebook   *Notebook `gorm:"foreignKey:NotebookID;constraint:OnDelete:SET NULL" json:"-"`
+	Tags       []Tag     `gorm:"many2many:note_tags;constraint:OnDelete:CASCADE" json:"tags,omitempty"`
+	// LastEditedByID is the user who last changed the title or content, which may be a user the note is shared with
+	LastEditedByID *uint `gorm:"index:idx_notes_last_edited_by_id" json:"last_edited_by_id"`
+	LastEditedBy   *User `gorm:"foreignKey:LastEditedByID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+// NotePage is one page of notes returned by GET /api/notes
+type NotePage struct {
+	Notes      []Note `json:"notes"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// NoteSearchResult is a note that matched a search, returned by GET /api/notes/search
+type NoteSearchResult struct {
+	Note Note    `json:"note"`
+	Rank float64 `json:"rank"`
+	// Snippet is an HTML-escaped excerpt with the matched terms wrapped in <mark> tags
+	Snippet string `json:"snippet"`
 }
 
 // TableName defines the table name
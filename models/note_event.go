@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// NoteEventType is the kind of change that happened to a note
+type NoteEventType string
+
+const (
+	// NoteEventCreated is emitted after a note is created
+	NoteEventCreated NoteEventType = "created"
+	// NoteEventUpdated is emitted after a note is updated
+	NoteEventUpdated NoteEventType = "updated"
+	// NoteEventDeleted is emitted after a note is deleted
+	NoteEventDeleted NoteEventType = "deleted"
+)
+
+// NoteEvent describes a change to a note, published for consumers such as the MCP server
+type NoteEvent struct {
+	Type       NoteEventType `json:"type"`
+	NoteID     uint          `json:"note_id"`
+	UserID     uint          `json:"user_id"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// NoteRevision is an immutable snapshot of a note's title and content, stored every time the note is written
+type NoteRevision struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	NoteID     uint   `gorm:"not null;uniqueIndex:idx_note_revisions_note_id_revision" json:"note_id"`
+	Note       *Note  `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"-"`
+	Revision   int    `gorm:"not null;uniqueIndex:idx_note_revisions_note_id_revision" json:"revision"`
+	Title      string `gorm:"not null" json:"title"`
+	Content    string `gorm:"type:text" json:"content"`
+	EditedByID *uint  `gorm:"index:idx_note_revisions_edited_by_id" json:"edited_by_id"`
+	EditedBy   *User  `gorm:"foreignKey:EditedByID;constraint:OnDelete:SET NULL" json:"-"`
+	// RestoredFrom is the revision that this revision restored, or nil for a normal edit
+	RestoredFrom *int      `json:"restored_from,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName defines the table name
+func (NoteRevision) TableName() string {
+	return "note_revisions"
+}
+
+// NoteDiffOp is the kind of change of a line in a NoteDiff
+type NoteDiffOp string
+
+const (
+	// NoteDiffEqual is a line found in both revisions
+	NoteDiffEqual NoteDiffOp = "equal"
+	// NoteDiffDelete is a line found only in the older revision
+	NoteDiffDelete NoteDiffOp = "delete"
+	// NoteDiffInsert is a line found only in the newer revision
+	NoteDiffInsert NoteDiffOp = "insert"
+)
+
+// NoteDiffLine is one line of a line-level diff; line numbers start at 1 and are 0 when the line is absent on that side
+type NoteDiffLine struct {
+	Op      NoteDiffOp `json:"op"`
+	OldLine int        `json:"old_line,omitempty"`
+	NewLine int        `json:"new_line,omitempty"`
+	Text    string     `json:"text"`
+}
+
+// NoteDiff is the line-level difference between two revisions of a note
+type NoteDiff struct {
+	NoteID   uint   `json:"note_id"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	OldTitle string `json:"old_title"`
+	NewTitle string `json:"new_title"`
+	// Lines compares the content line by line
+	Lines []NoteDiffLine `json:"lines"`
+	// Unified is the same comparison of the content in unified diff format
+	Unified string `json:"unified"`
+}
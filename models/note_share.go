@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// NotePermission is the access another user is granted to a shared note
+type NotePermission string
+
+const (
+	// NotePermissionRead allows reading the note
+	NotePermissionRead NotePermission = "read"
+	// NotePermissionEdit allows reading and editing the title and content of the note
+	NotePermissionEdit NotePermission = "edit"
+)
+
+// Valid checks if the permission is a known value
+func (p NotePermission) Valid() bool {
+	return p == NotePermissionRead || p == NotePermissionEdit
+}
+
+// NoteShare grants a user other than the owner access to a note
+type NoteShare struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	NoteID     uint           `gorm:"not null;uniqueIndex:idx_note_shares_note_id_user_id" json:"note_id"`
+	Note       *Note          `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID     uint           `gorm:"not null;uniqueIndex:idx_note_shares_note_id_user_id;index:idx_note_shares_user_id" json:"user_id"`
+	User       *User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Permission NotePermission `gorm:"type:varchar(10);not null" json:"permission"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// TableName defines the table name
+func (NoteShare) TableName() string {
+	return "note_shares"
+}
+
+// NoteShareInfo describes who a note is shared with, returned by GET /api/notes/:id/shares
+type NoteShareInfo struct {
+	UserID     uint           `json:"user_id"`
+	Email      string         `json:"email"`
+	FirstName  string         `json:"first_name"`
+	LastName   string         `json:"last_name"`
+	Permission NotePermission `json:"permission"`
+	SharedAt   time.Time      `json:"shared_at"`
+}
+
+// SharedNote is a note another user shared with the caller, returned by GET /api/notes/shared
+type SharedNote struct {
+	Note       Note           `json:"note"`
+	OwnerEmail string         `json:"owner_email"`
+	Permission NotePermission `json:"permission"`
+	SharedAt   time.Time      `json:"shared_at"`
+}
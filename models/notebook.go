@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Notebook groups notes; notebooks can be nested to form a hierarchy owned by a user
+type Notebook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index:idx_notebooks_user_id" json:"user_id"`
+	ParentID  *uint     `gorm:"index:idx_notebooks_parent_id" json:"parent_id"`
+	Parent    *Notebook `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT" json:"-"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName defines the table name
+func (Notebook) TableName() string {
+	return "notebooks"
+}
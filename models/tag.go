@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Tag is a label a user attaches to notes; tag names are unique per user
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_tags_user_id_name" json:"user_id"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_id_name" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName defines the table name
+func (Tag) TableName() string {
+	return "tags"
+}
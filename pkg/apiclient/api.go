@@ -0,0 +1,328 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Napat/mcpserver-demo/models"
+)
+
+// maxNotePageSize คือจำนวนบันทึกต่อหน้าสูงสุดที่ GET /api/notes รองรับ
+const maxNotePageSize = 100
+
+// LoginRequest คือข้อมูลสำหรับ POST /api/auth/login
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RegisterRequest คือข้อมูลสำหรับ POST /api/auth/register
+type RegisterRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Gender    string `json:"gender"`
+}
+
+// AuthResponse คือ response ของการล็อกอินและลงทะเบียน
+type AuthResponse struct {
+	Token string      `json:"token"`
+	User  models.User `json:"user"`
+}
+
+// ProfileUpdateRequest คือข้อมูลสำหรับ PUT /api/me
+type ProfileUpdateRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Gender    string `json:"gender"`
+}
+
+// NoteRequest คือข้อมูลสำหรับสร้างหรือแก้ไขบันทึก
+type NoteRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// NoteListQuery คือ query parameter ของ GET /api/notes ค่าที่ว่างจะใช้ค่าเริ่มต้นของ API
+type NoteListQuery struct {
+	// Limit คือจำนวนบันทึกต่อหน้า (1-100)
+	Limit int
+	// Cursor คือ NextCursor หรือ PrevCursor ของหน้าก่อนหน้า
+	Cursor string
+	// Sort คือ created_at, updated_at หรือ title
+	Sort string
+	// Order คือ asc หรือ desc
+	Order string
+	// Title เลือกเฉพาะบันทึกที่ชื่อมีข้อความนี้ ส่วน TitlePrefix เลือกเฉพาะบันทึกที่ชื่อขึ้นต้นด้วยข้อความนี้ (ไม่สนตัวพิมพ์ทั้งคู่)
+	Title         string
+	TitlePrefix   string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	// Tags เลือกเฉพาะบันทึกที่มีทุก tag
+	Tags []string
+	// NotebookID คือ ID ของสมุดบันทึก หรือ "none" สำหรับบันทึกที่ไม่อยู่ในสมุดบันทึกใด
+	NotebookID string
+}
+
+// values แปลง query เป็น query string
+func (q NoteListQuery) values() url.Values {
+	values := url.Values{}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	setIfNotEmpty := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	setIfNotEmpty("cursor", q.Cursor)
+	setIfNotEmpty("sort", q.Sort)
+	setIfNotEmpty("order", q.Order)
+	setIfNotEmpty("title", q.Title)
+	setIfNotEmpty("title_prefix", q.TitlePrefix)
+	setIfNotEmpty("notebook_id", q.NotebookID)
+	for _, tag := range q.Tags {
+		values.Add("tag", tag)
+	}
+
+	times := map[string]*time.Time{
+		"created_after":  q.CreatedAfter,
+		"created_before": q.CreatedBefore,
+		"updated_after":  q.UpdatedAfter,
+		"updated_before": q.UpdatedBefore,
+	}
+	for name, t := range times {
+		if t != nil {
+			values.Set(name, t.Format(time.RFC3339))
+		}
+	}
+	return values
+}
+
+// UpdateRoleRequest คือข้อมูลสำหรับ PUT /api/admin/users/{id}/role
+type UpdateRoleRequest struct {
+	Role models.UserRole `json:"role"`
+}
+
+// visitorResponse คือ response ของ /api/visitors
+type visitorResponse struct {
+	Count int64 `json:"visitor_count"`
+}
+
+// Login ล็อกอินผ่าน POST /api/auth/login
+func (c *Client) Login(ctx context.Context, req LoginRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auth/login", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Register ลงทะเบียนผู้ใช้ใหม่ผ่าน POST /api/auth/register
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auth/register", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetProfile ดึงข้อมูลผู้ใช้ปัจจุบันผ่าน GET /api/me
+func (c *Client) GetProfile(ctx context.Context) (*models.User, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodGet, "/api/me", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile แก้ไขข้อมูลผู้ใช้ปัจจุบันผ่าน PUT /api/me
+func (c *Client) UpdateProfile(ctx context.Context, req ProfileUpdateRequest) (*models.User, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodPut, "/api/me", req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UploadProfileImage อัปโหลดรูปโปรไฟล์ผ่าน POST /api/me/profile-image (multipart field "image")
+// และคืน URL ของรูปใหม่
+func (c *Client) UploadProfileImage(ctx context.Context, filename, contentType string, data []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="image"; filename=%q`, filename))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+
+	var resp struct {
+		ImageURL string `json:"image_url"`
+	}
+	if err := c.doRaw(ctx, http.MethodPost, "/api/me/profile-image", body.Bytes(), writer.FormDataContentType(), &resp); err != nil {
+		return "", err
+	}
+	return resp.ImageURL, nil
+}
+
+// GetLoginHistory ดึงประวัติการเข้าสู่ระบบผ่าน GET /api/me/login-history
+func (c *Client) GetLoginHistory(ctx context.Context, limit int) ([]models.LoginHistory, error) {
+	path := "/api/me/login-history"
+	if limit > 0 {
+		path = fmt.Sprintf("%s?limit=%d", path, limit)
+	}
+
+	var history []models.LoginHistory
+	if err := c.do(ctx, http.MethodGet, path, nil, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// ListNotes ดึงบันทึกทั้งหมดของผู้ใช้ (ใหม่สุดก่อน) โดยไล่ทุกหน้าของ GET /api/notes
+func (c *Client) ListNotes(ctx context.Context) ([]models.Note, error) {
+	return c.ListAllNotes(ctx, NoteListQuery{})
+}
+
+// ListAllNotes ดึงบันทึกทุกหน้าของ GET /api/notes ที่ตรงกับ query โดยไม่สนใจ Limit และ Cursor ของ query
+func (c *Client) ListAllNotes(ctx context.Context, query NoteListQuery) ([]models.Note, error) {
+	notes := []models.Note{}
+	query.Limit = maxNotePageSize
+	query.Cursor = ""
+	for {
+		page, err := c.ListNotesPage(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, page.Notes...)
+		if page.NextCursor == "" {
+			return notes, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// ListNotesPage ดึงบันทึกหนึ่งหน้าผ่าน GET /api/notes ตาม query
+func (c *Client) ListNotesPage(ctx context.Context, query NoteListQuery) (*models.NotePage, error) {
+	path := "/api/notes"
+	if values := query.values(); len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+
+	var page models.NotePage
+	if err := c.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetNote ดึงบันทึกผ่าน GET /api/notes/{id}
+func (c *Client) GetNote(ctx context.Context, id uint) (*models.Note, error) {
+	var note models.Note
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/notes/%d", id), nil, &note); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// CreateNote สร้างบันทึกผ่าน POST /api/notes
+func (c *Client) CreateNote(ctx context.Context, req NoteRequest) (*models.Note, error) {
+	var note models.Note
+	if err := c.do(ctx, http.MethodPost, "/api/notes", req, &note); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// UpdateNote แก้ไขบันทึกผ่าน PUT /api/notes/{id}
+func (c *Client) UpdateNote(ctx context.Context, id uint, req NoteRequest) (*models.Note, error) {
+	var note models.Note
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/notes/%d", id), req, &note); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// DeleteNote ลบบันทึกผ่าน DELETE /api/notes/{id}
+func (c *Client) DeleteNote(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/notes/%d", id), nil, nil)
+}
+
+// GetVisitorCount ดึงจำนวนผู้เข้าชมผ่าน GET /api/visitors
+func (c *Client) GetVisitorCount(ctx context.Context) (int64, error) {
+	var resp visitorResponse
+	if err := c.do(ctx, http.MethodGet, "/api/visitors", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// IncrementVisitorCount เพิ่มจำนวนผู้เข้าชมผ่าน POST /api/visitors
+func (c *Client) IncrementVisitorCount(ctx context.Context) (int64, error) {
+	var resp visitorResponse
+	if err := c.do(ctx, http.MethodPost, "/api/visitors", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// ListUsers ดึงรายชื่อผู้ใช้ทั้งหมดผ่าน GET /api/admin/users (ต้องเป็น admin)
+func (c *Client) ListUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	if err := c.do(ctx, http.MethodGet, "/api/admin/users", nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// DeactivateUser ปิดการใช้งานผู้ใช้ผ่าน POST /api/admin/users/{id}/deactivate (ต้องเป็น admin)
+func (c *Client) DeactivateUser(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/admin/users/%d/deactivate", id), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUserRole เปลี่ยนบทบาทของผู้ใช้ผ่าน PUT /api/admin/users/{id}/role (ต้องเป็น admin)
+func (c *Client) UpdateUserRole(ctx context.Context, id uint, req UpdateRoleRequest) (*models.User, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/admin/users/%d/role", id), req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserLoginHistory ดึงประวัติการเข้าสู่ระบบของผู้ใช้ใดก็ได้ผ่าน GET /api/admin/users/{id}/login-history (ต้องเป็น admin)
+func (c *Client) GetUserLoginHistory(ctx context.Context, id uint, limit int) ([]models.LoginHistory, error) {
+	path := fmt.Sprintf("/api/admin/users/%d/login-history", id)
+	if limit > 0 {
+		path = fmt.Sprintf("%s?limit=%d", path, limit)
+	}
+
+	var history []models.LoginHistory
+	if err := c.do(ctx, http.MethodGet, path, nil, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
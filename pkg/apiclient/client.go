@@ -0,0 +1,231 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 2
+	defaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 2 * time.Second
+)
+
+// APIError คือ error ที่ API ตอบกลับมาด้วย status code ที่ไม่ใช่ 2xx
+type APIError struct {
+	// StatusCode คือ HTTP status code ที่ได้รับ
+	StatusCode int
+	// Message คือข้อความจาก field "message" หรือ "error" ของ response หรือ body ทั้งหมดถ้าไม่ใช่ JSON
+	Message string
+}
+
+// Error คืนข้อความของ error
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// StatusCode คืน HTTP status code ของ err ถ้าเป็น *APIError หรือ 0 ถ้าไม่ใช่
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// Client คือ HTTP client สำหรับเรียก REST API (/api) ของ cmd/api
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Option คือฟังก์ชันสำหรับปรับแต่ง Client
+type Option func(*Client)
+
+// WithHTTPClient กำหนด http.Client ที่ใช้ส่ง request
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout กำหนด timeout ของแต่ละ request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetries กำหนดจำนวนครั้งที่ลองใหม่และเวลารอเริ่มต้น (เพิ่มขึ้นเป็นสองเท่าทุกครั้ง)
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// New สร้าง Client ใหม่สำหรับ API ที่ baseURL เช่น http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithToken คืน Client ใหม่ที่แนบ JWT token ไปกับทุก request โดยใช้ http.Client ร่วมกัน
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.token = token
+	return &clone
+}
+
+// BaseURL คืน base URL ของ API
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// do ส่ง payload เป็น JSON ไปที่ path และแปลง response เป็น out (ถ้าไม่เป็น nil)
+func (c *Client) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body []byte
+	contentType := ""
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = data
+		contentType = "application/json"
+	}
+
+	return c.doRaw(ctx, method, path, body, contentType, out)
+}
+
+// doRaw ส่ง body ตาม contentType ไปที่ path และแปลง response เป็น out (ถ้าไม่เป็น nil)
+// request ที่ไม่ใช่ POST จะถูกลองใหม่เมื่อเกิด network error หรือได้รับ 5xx
+// POST จะไม่ถูกลองใหม่เพื่อป้องกันการสร้างข้อมูลซ้ำ
+func (c *Client) doRaw(ctx context.Context, method, path string, body []byte, contentType string, out interface{}) error {
+	retries := c.maxRetries
+	if method == http.MethodPost {
+		retries = 0
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return err
+			}
+		}
+
+		respBody, retry, err := c.send(ctx, method, path, body, contentType)
+		if err == nil {
+			if out == nil || len(respBody) == 0 {
+				return nil
+			}
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			return nil
+		}
+
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// send ส่ง request หนึ่งครั้งและคืน body เมื่อได้รับ status 2xx
+// retry เป็น true เมื่อ error เกิดจาก network หรือ status 5xx
+func (c *Client) send(ctx context.Context, method, path string, body []byte, contentType string) ([]byte, bool, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: errorMessage(respBody)}
+		return nil, resp.StatusCode >= http.StatusInternalServerError, apiErr
+	}
+	return respBody, false, nil
+}
+
+// wait รอตามเวลา backoff ของครั้งที่ attempt หรือจนกว่า ctx จะถูกยกเลิก
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := c.backoff << (attempt - 1)
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// errorMessage ดึงข้อความ error จาก response body ในรูปแบบ {"message": ...} ของ Echo หรือ {"error": ...}
+func errorMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Message != "" {
+			return payload.Message
+		}
+		if payload.Error != "" {
+			return payload.Error
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
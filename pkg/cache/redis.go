@@ -57,3 +57,13 @@ func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, ex
 func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	return r.Client.Incr(ctx, key).Result()
 }
+
+// Publish ส่งข้อความไปยัง channel ของ Redis pub/sub
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.Client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe สมัครรับข้อความจาก channel ของ Redis pub/sub
+func (r *RedisClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return r.Client.Subscribe(ctx, channels...)
+}
@@ -15,6 +15,11 @@ var DB *gorm.DB
 
 // Connect connects to PostgreSQL database
 func Connect() (*gorm.DB, error) {
+	return ConnectWithLogger(logger.Default.LogMode(logger.Info))
+}
+
+// ConnectWithLogger connects to PostgreSQL database using the given GORM logger
+func ConnectWithLogger(gormLogger logger.Interface) (*gorm.DB, error) {
 	var err error
 
 	host := os.Getenv("DB_HOST")
@@ -47,7 +52,7 @@ func Connect() (*gorm.DB, error) {
 		host, port, user, password, dbname, sslmode)
 
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: gormLogger,
 	})
 
 	if err != nil {
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -16,6 +17,16 @@ type JWTConfig struct {
 	Secret string
 }
 
+// claimsContextKey is the request context key of the claims verified by JWTMiddleware
+type claimsContextKey struct{}
+
+// ClaimsFromContext returns the claims that JWTMiddleware verified for the request
+// It is meant for plain http.Handler code mounted behind JWTMiddleware, which cannot read the echo context
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
 // getJWTSecret retrieves the secret key from the environment
 func getJWTSecret() string {
 	secret := os.Getenv("JWT_SECRET")
@@ -66,6 +77,8 @@ func JWTMiddleware() echo.MiddlewareFunc {
 			// Retrieve claims from token
 			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 				c.Set("user", claims)
+				req := c.Request()
+				c.SetRequest(req.WithContext(context.WithValue(req.Context(), claimsContextKey{}, claims)))
 				return next(c)
 			}
 
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"fmt"
+	"log"
 	"mime/multipart"
 	"os"
 
@@ -83,7 +84,7 @@ func (s *MinioStorage) UploadFile(bucketName string, objectName string, file *mu
 
 		err = s.client.SetBucketPolicy(ctx, bucketName, policy)
 		if err != nil {
-			fmt.Printf("Warning: Failed to set bucket policy: %v\n", err)
+			log.Printf("Warning: Failed to set bucket policy: %v", err)
 			// ไม่ return error เพื่อให้ยังคงอัปโหลดไฟล์ได้
 		}
 	}